@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/analyzer"
+	"github.com/hel1th/loglinter/pkg/compliance"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// complianceReport lists the log calls of packages that may leak data
+// regulated by compliance profiles, grouped per profile. It returns the exit
+// code: 0 when nothing was found, 1 on errors and 3 when there is evidence.
+func complianceReport(args []string) int {
+	flags := flag.NewFlagSet("compliance-report", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: loglinter compliance-report [-profiles gdpr,pci-dss,hipaa] [packages]\n")
+		flags.PrintDefaults()
+	}
+	names := flags.String("profiles", "", "comma-separated compliance profiles to report on; defaults to the configured ones, or all")
+	flags.Parse(args)
+
+	selected := analyzer.ComplianceProfiles()
+	if *names != "" {
+		selected = strings.Split(*names, ",")
+	}
+
+	profiles := compliance.Profiles
+	if len(selected) > 0 {
+		var err error
+		profiles, err = compliance.Lookup(selected)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	analyzer.SetComplianceProfiles(compliance.NamesOf(profiles))
+	// a report without the rule would find nothing
+	analyzer.EnableComplianceEvidence()
+
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{Mode: packages.LoadAllSyntax, Tests: true}, patterns...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return 1
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{analyzer.Analyzer}, pkgs, nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	evidence := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			fmt.Fprintln(os.Stderr, act.Err)
+			return 1
+		}
+
+		result, ok := act.Result.(*analyzer.Result)
+		if !ok {
+			continue
+		}
+
+		for _, e := range result.Compliance {
+			line := fmt.Sprintf("%s: %s", act.Package.Fset.Position(e.Pos), e.Message)
+			// test variants of a package repeat its log calls
+			if seen[e.Profile+line] {
+				continue
+			}
+			seen[e.Profile+line] = true
+			evidence[e.Profile] = append(evidence[e.Profile], line)
+		}
+	}
+
+	found := false
+	for _, p := range profiles {
+		fmt.Printf("%s (%s): %d log calls\n", p.Title, p.Name, len(evidence[p.Name]))
+		for _, line := range evidence[p.Name] {
+			fmt.Printf("\t%s\n", line)
+			found = true
+		}
+	}
+
+	if found {
+		return 3
+	}
+
+	return 0
+}
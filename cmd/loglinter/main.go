@@ -1,10 +1,16 @@
 package main
 
 import (
+	"os"
+
 	"github.com/hel1th/loglinter/pkg/analyzer"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "compliance-report" {
+		os.Exit(complianceReport(os.Args[2:]))
+	}
+
 	singlechecker.Main(analyzer.Analyzer)
 }
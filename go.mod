@@ -4,6 +4,7 @@ go 1.25.6
 
 require (
 	github.com/golangci/plugin-module-register v0.1.2
+	golang.org/x/text v0.34.0
 	golang.org/x/tools v0.42.0
 )
 
@@ -2,11 +2,17 @@ package analyzer
 
 import (
 	"os"
+	"reflect"
 	"slices"
 
+	"github.com/hel1th/loglinter/pkg/annotations"
+	"github.com/hel1th/loglinter/pkg/compliance"
 	"github.com/hel1th/loglinter/pkg/config"
+	"github.com/hel1th/loglinter/pkg/dictionaries"
 	"github.com/hel1th/loglinter/pkg/loggers"
 	"github.com/hel1th/loglinter/pkg/rules"
+	"github.com/hel1th/loglinter/pkg/secrets"
+	"github.com/hel1th/loglinter/pkg/taint"
 	"github.com/joho/godotenv"
 	"golang.org/x/tools/go/analysis"
 )
@@ -15,11 +21,31 @@ var Analyzer = &analysis.Analyzer{
 	Name:             "loglinter",
 	Doc:              "checks log messages for bad patterns",
 	Run:              run,
-	Requires:         []*analysis.Analyzer{},
+	Requires:         []*analysis.Analyzer{FactsAnalyzer, taint.SSAAnalyzer},
+	ResultType:       reflect.TypeFor[*Result](),
 	RunDespiteErrors: false,
 }
+
+// FactsAnalyzer exports the sensitive declarations and secret sources of
+// packages as facts. Drivers run analyzers with facts on every dependency,
+// so it requires nothing: SSA and the rules only run on the packages being
+// analyzed.
+var FactsAnalyzer = &analysis.Analyzer{
+	Name:       "loglinterfacts",
+	Doc:        "exports sensitive declarations and secret sources for loglinter",
+	Run:        exportFacts,
+	FactTypes:  []analysis.Fact{new(annotations.SensitiveFact), new(annotations.SourceFact)},
+	ResultType: reflect.TypeFor[*annotations.Facts](),
+}
+
 var cfg *config.Config
 
+// Result is what the analyzer found in a package besides diagnostics.
+type Result struct {
+	// log calls that may leak data regulated by a compliance profile
+	Compliance []compliance.Evidence
+}
+
 func init() {
 	godotenv.Load(".env")
 
@@ -31,8 +57,15 @@ func init() {
 }
 
 func run(pass *analysis.Pass) (any, error) {
+	ruleSet, err := createRuleSet()
+	if err != nil {
+		return nil, err
+	}
+	sensitiveRule := findSensitiveDataRule(ruleSet)
+
 	detector := loggers.NewDetector(pass)
-	ruleSet := createRuleSet()
+	detector.SetPrintfWrappers(cfg.PrintfWrappers)
+	detector.SetSlogWrappers(cfg.SlogWrappers)
 
 	for _, file := range pass.Files {
 		logCalls := detector.DetectLogCalls(file)
@@ -46,17 +79,218 @@ func run(pass *analysis.Pass) (any, error) {
 		}
 	}
 
-	return nil, nil
+	for _, diag := range ruleSet.CheckPackage(pass) {
+		pass.Report(diag)
+	}
+
+	result := &Result{}
+	if sensitiveRule != nil {
+		result.Compliance = sensitiveRule.Evidence()
+	}
+
+	return result, nil
+}
+
+func exportFacts(pass *analysis.Pass) (any, error) {
+	opts := annotations.Options{OmitTags: cfg.SensitiveOmitTags}
+
+	// sources with an argument pattern depend on the call, they can't mark
+	// the function
+	taintSources, err := parseTaintSources(cfg.TaintSources)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range taintSources {
+		if source.Arg == "" {
+			opts.Sources = append(opts.Sources, source.Func)
+		}
+	}
+
+	// sources are inferred from field names only in the main module,
+	// dependencies and the standard library have no version or no module
+	inModule := pass.Module != nil && pass.Module.Path != "" && pass.Module.Version == ""
+	if inModule && shouldEnableRule((&rules.SensitiveDataRule{}).Name()) {
+		nameRule, err := newSensitiveNameRule()
+		if err != nil {
+			return nil, err
+		}
+		opts.SensitiveName = nameRule.IsCredentialName
+	}
+
+	annotations.Export(pass, opts)
+
+	return annotations.NewFacts(pass), nil
+}
+
+// newSensitiveNameRule returns a no-sensitive-data rule configured to match
+// names, for inferring secret sources.
+func newSensitiveNameRule() (*rules.SensitiveDataRule, error) {
+	rule := &rules.SensitiveDataRule{}
+	rule.SetCustomPatterns(cfg.CustomSensitivePatterns)
+	rule.SetBenignPhrases(cfg.BenignSensitivePhrases)
+
+	dicts, err := dictionaries.Lookup(cfg.SensitiveLanguages)
+	if err != nil {
+		return nil, err
+	}
+	rule.SetLanguages(dicts)
+
+	profiles, err := compliance.Lookup(cfg.Compliance)
+	if err != nil {
+		return nil, err
+	}
+	rule.SetProfiles(profiles)
+
+	return rule, nil
+}
+
+// findSensitiveDataRule returns the no-sensitive-data rule of a rule set, or
+// nil if it is disabled.
+func findSensitiveDataRule(ruleSet *rules.RuleSet) *rules.SensitiveDataRule {
+	for _, rule := range ruleSet.GetRules() {
+		if sensitiveRule, ok := rule.(*rules.SensitiveDataRule); ok {
+			return sensitiveRule
+		}
+	}
+
+	return nil
+}
+
+// ComplianceProfiles returns the names of the configured compliance profiles.
+func ComplianceProfiles() []string {
+	return cfg.Compliance
+}
+
+// SetComplianceProfiles overrides the configured compliance profiles.
+func SetComplianceProfiles(names []string) {
+	cfg.Compliance = names
 }
 
-func createRuleSet() *rules.RuleSet {
+// EnableComplianceEvidence enables no-sensitive-data, which gathers the
+// evidence of compliance reports, even if the configuration disables it.
+func EnableComplianceEvidence() {
+	cfg.Rules.NoSensitiveData.Enabled = true
+}
+
+func createRuleSet() (*rules.RuleSet, error) {
 	ruleSet := rules.NewRuleSet()
 
+	taintSources, err := parseTaintSources(cfg.TaintSources)
+	if err != nil {
+		return nil, err
+	}
+
+	secretOptions := secrets.Options{
+		Disabled:         cfg.SecretDetectors.Disabled,
+		EntropyThreshold: cfg.SecretDetectors.EntropyThreshold,
+		EntropyMinLength: cfg.SecretDetectors.EntropyMinLength,
+	}
+	if err := secretOptions.Validate(); err != nil {
+		return nil, err
+	}
+
+	sensitiveRule := &rules.SensitiveDataRule{}
+	sensitiveRule.SetCustomPatterns(cfg.CustomSensitivePatterns)
+	sensitiveRule.SetBenignPhrases(cfg.BenignSensitivePhrases)
+	sensitiveRule.SetTaintSources(taintSources)
+	sensitiveRule.SetSecretDetectors(secrets.Detectors(secretOptions))
+	sensitiveRule.SetMinConfidence(cfg.SensitiveMinConfidence)
+
+	severities, err := parseSeverities(cfg.SensitiveSeverity)
+	if err != nil {
+		return nil, err
+	}
+	sensitiveRule.SetSeverities(severities)
+
+	customCategories, err := parseCustomCategories(cfg.CustomSensitiveCategories)
+	if err != nil {
+		return nil, err
+	}
+	sensitiveRule.SetCustomCategories(customCategories)
+
+	dicts, err := dictionaries.Lookup(cfg.SensitiveLanguages)
+	if err != nil {
+		return nil, err
+	}
+	sensitiveRule.SetLanguages(dicts)
+
+	profiles, err := compliance.Lookup(cfg.Compliance)
+	if err != nil {
+		return nil, err
+	}
+	sensitiveRule.SetProfiles(profiles)
+
+	structRule := &rules.SensitiveStructRule{}
+	structRule.SetCustomPatterns(cfg.CustomSensitivePatterns)
+	structRule.SetBenignPhrases(cfg.BenignSensitivePhrases)
+	structRule.SetLanguages(dicts)
+	structRule.SetMaxDepth(cfg.SensitiveStructDepth)
+
+	methodRule := &rules.SensitiveMethodRule{}
+	methodRule.SetCustomPatterns(cfg.CustomSensitivePatterns)
+	methodRule.SetBenignPhrases(cfg.BenignSensitivePhrases)
+	methodRule.SetLanguages(dicts)
+
+	untrustedSources, err := parseSources(cfg.UntrustedSources)
+	if err != nil {
+		return nil, err
+	}
+
+	injectionRule := &rules.LogInjectionRule{}
+	injectionRule.SetSources(untrustedSources)
+	injectionRule.SetSanitizers(cfg.LogSanitizers)
+
+	styleRule := &rules.KeyStyleRule{}
+	if err := styleRule.SetStyle(cfg.KeyStyle); err != nil {
+		return nil, err
+	}
+	// fixes inferring keys write them in the configured style
+	keyStyle := rules.KeyStyle(cfg.KeyStyle)
+
+	dynamicRule := &rules.DynamicMessageRule{}
+	dynamicRule.SetCheckUnstructured(cfg.DynamicMessageUnstructured)
+	dynamicRule.SetKeyStyle(keyStyle)
+
+	verbsRule := &rules.PrintfVerbsRule{}
+	verbsRule.SetKeyStyle(keyStyle)
+
+	keyValueRule := &rules.KeyValueRule{}
+	keyValueRule.SetKeyStyle(keyStyle)
+
+	keySchema, err := config.LoadKeySchema(cfg.KeySchema)
+	if err != nil {
+		return nil, err
+	}
+	schemaRule := &rules.KeySchemaRule{}
+	if err := schemaRule.SetSchema(keySchema.Keys); err != nil {
+		return nil, err
+	}
+
+	semconvRule := &rules.SemconvRule{}
+	if err := semconvRule.SetVersion(cfg.SemconvVersion); err != nil {
+		return nil, err
+	}
+	if shouldEnableRule(semconvRule.Name()) {
+		styleRule.SetConventions(semconvRule.Conventions())
+	}
+
 	rulesList := []rules.Rule{
 		&rules.LowercaseRule{},
 		&rules.EnglishOnlyRule{},
 		&rules.NoSpecialSymbolsRule{},
-		&rules.SensitiveDataRule{},
+		sensitiveRule,
+		structRule,
+		&rules.SensitiveTypeRule{},
+		methodRule,
+		injectionRule,
+		&rules.PrintfRule{},
+		dynamicRule,
+		verbsRule,
+		keyValueRule,
+		styleRule,
+		schemaRule,
+		semconvRule,
+		&rules.TypedAttrsRule{},
 	}
 
 	for _, rule := range rulesList {
@@ -65,7 +299,62 @@ func createRuleSet() *rules.RuleSet {
 		}
 	}
 
-	return ruleSet
+	return ruleSet, nil
+}
+
+func parseTaintSources(configured []string) ([]taint.Source, error) {
+	if len(configured) == 0 {
+		configured = rules.DefaultTaintSources
+	}
+
+	return parseSources(configured)
+}
+
+func parseSources(configured []string) ([]taint.Source, error) {
+	if len(configured) == 0 {
+		return nil, nil
+	}
+
+	sources := make([]taint.Source, 0, len(configured))
+	for _, s := range configured {
+		source, err := taint.ParseSource(s)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+func parseSeverities(configured map[string]string) (map[rules.Category]rules.Severity, error) {
+	severities := make(map[rules.Category]rules.Severity, len(configured))
+	for c, s := range configured {
+		category, err := rules.ParseCategory(c)
+		if err != nil {
+			return nil, err
+		}
+		severity, err := rules.ParseSeverity(s)
+		if err != nil {
+			return nil, err
+		}
+		severities[category] = severity
+	}
+
+	return severities, nil
+}
+
+func parseCustomCategories(configured map[string]string) (map[string]rules.Category, error) {
+	categories := make(map[string]rules.Category, len(configured))
+	for pattern, c := range configured {
+		category, err := rules.ParseCategory(c)
+		if err != nil {
+			return nil, err
+		}
+		categories[pattern] = category
+	}
+
+	return categories, nil
 }
 
 func shouldEnableRule(ruleName string) bool {
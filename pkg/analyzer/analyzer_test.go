@@ -0,0 +1,17 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("..", "..", "testdata", "src", "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, Analyzer, "./...")
+}
@@ -0,0 +1,259 @@
+// Package annotations finds types, struct fields and functions marked as
+// sensitive in source and shares them between packages as analysis facts.
+//
+// A declaration is marked by a //loglinter:sensitive directive in its doc
+// comment, and a struct field also by a loglinter:"sensitive" tag:
+//
+//	//loglinter:sensitive
+//	type Token string
+//
+//	type User struct {
+//		Pin string `loglinter:"sensitive"`
+//		Dob string //loglinter:sensitive
+//	}
+//
+// A function returning secrets is marked by a //loglinter:secret-source
+// directive, or found by what it returns:
+//
+//	//loglinter:secret-source
+//	func ReadSecret(path string) string
+//
+//	func (c *Config) DatabasePassword() string { return c.DB.Password }
+package annotations
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const directive = "//loglinter:sensitive"
+
+// SensitiveFact marks a type, a struct field or a function whose results
+// hold sensitive data.
+type SensitiveFact struct {
+	// how the object was marked, like //loglinter:sensitive
+	Reason string
+}
+
+func (*SensitiveFact) AFact() {}
+
+func (f *SensitiveFact) String() string {
+	return "sensitive(" + f.Reason + ")"
+}
+
+// Options selects which annotations mark fields as sensitive besides the
+// loglinter tag and directive, and which functions are secret sources
+// besides annotated ones.
+type Options struct {
+	// treat fields hidden with log:"-" or json:"-" as sensitive
+	OmitTags bool
+	// full names of functions returning secrets, like
+	// (*example.com/vault.Client).ReadSecret
+	Sources []string
+	// reports whether a field is confidently named like a secret;
+	// functions returning such fields are secret sources. Left nil for
+	// packages outside the analyzed module, names there aren't evidence
+	// enough to mark their functions for every importer.
+	SensitiveName func(name string) bool
+}
+
+// Export exports a SensitiveFact for every annotated declaration of the
+// package and a SourceFact for every secret source. The analyzer must list
+// both in its FactTypes, and return NewFacts for the analyzers requiring it.
+func Export(pass *analysis.Pass, opts Options) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch decl := n.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					spec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+
+					doc := spec.Doc
+					// a single type declared without parentheses has its
+					// comment on the declaration
+					if doc == nil && !decl.Lparen.IsValid() {
+						doc = decl.Doc
+					}
+
+					if hasDirective(doc, directive) {
+						export(pass, spec.Name, directive)
+					}
+				}
+
+			case *ast.FuncDecl:
+				if hasDirective(decl.Doc, directive) {
+					export(pass, decl.Name, directive)
+				}
+
+			case *ast.Field:
+				reason := fieldReason(decl, opts)
+				if reason == "" {
+					return true
+				}
+				for _, name := range decl.Names {
+					export(pass, name, reason)
+				}
+				if len(decl.Names) == 0 {
+					// embedded field
+					if ident := embeddedName(decl.Type); ident != nil {
+						export(pass, ident, reason)
+					}
+				}
+			}
+
+			return true
+		})
+	}
+
+	// inferring sources needs the facts of fields
+	exportSources(pass, opts)
+}
+
+func export(pass *analysis.Pass, name *ast.Ident, reason string) {
+	obj := pass.TypesInfo.Defs[name]
+	if obj == nil || obj.Pkg() != pass.Pkg {
+		return
+	}
+
+	// methods, parameters and results are only used within the package
+	if v, ok := obj.(*types.Var); ok && !v.IsField() {
+		return
+	}
+
+	pass.ExportObjectFact(obj, &SensitiveFact{Reason: reason})
+}
+
+// fieldReason returns why a struct field is sensitive, or "" if it isn't.
+func fieldReason(field *ast.Field, opts Options) string {
+	if hasDirective(field.Doc, directive) || hasDirective(field.Comment, directive) {
+		return directive
+	}
+
+	if field.Tag == nil {
+		return ""
+	}
+
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	tag := reflect.StructTag(raw)
+
+	if tag.Get("loglinter") == "sensitive" {
+		return `tag loglinter:"sensitive"`
+	}
+
+	if opts.OmitTags {
+		for _, key := range []string{"log", "json"} {
+			if tag.Get(key) == "-" {
+				return "tag " + key + `:"-"`
+			}
+		}
+	}
+
+	return ""
+}
+
+func embeddedName(expr ast.Expr) *ast.Ident {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	}
+
+	return nil
+}
+
+func hasDirective(doc *ast.CommentGroup, name string) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, c := range doc.List {
+		text := strings.TrimSpace(c.Text)
+		if text == name || strings.HasPrefix(text, name+" ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Facts is the result of an analyzer calling Export. Facts belong to the
+// analyzer exporting them, so analyzers requiring it import them through
+// its result.
+type Facts struct {
+	importObjectFact func(obj types.Object, fact analysis.Fact) bool
+}
+
+// NewFacts returns the facts exported by Export on pass and its
+// dependencies.
+func NewFacts(pass *analysis.Pass) *Facts {
+	return &Facts{importObjectFact: pass.ImportObjectFact}
+}
+
+// importFact imports a fact from the result of a required analyzer calling
+// Export, or from pass itself if it calls Export.
+func importFact(pass *analysis.Pass, obj types.Object, fact analysis.Fact) bool {
+	for _, result := range pass.ResultOf {
+		if facts, ok := result.(*Facts); ok {
+			return facts.importObjectFact(obj, fact)
+		}
+	}
+
+	return pass.ImportObjectFact != nil && pass.ImportObjectFact(obj, fact)
+}
+
+// Sensitive reports whether an object of this or an imported package was
+// marked as sensitive, and how.
+func Sensitive(pass *analysis.Pass, obj types.Object) (string, bool) {
+	if obj == nil || obj.Pkg() == nil {
+		return "", false
+	}
+
+	// facts are attached to generic declarations, not their instances
+	switch o := obj.(type) {
+	case *types.Var:
+		obj = o.Origin()
+	case *types.Func:
+		obj = o.Origin()
+	}
+
+	var fact SensitiveFact
+	if !importFact(pass, obj, &fact) {
+		return "", false
+	}
+
+	return fact.Reason, true
+}
+
+// SensitiveType reports whether a type, or the type a pointer points to, was
+// marked as sensitive.
+func SensitiveType(pass *analysis.Pass, typ types.Type) (string, bool) {
+	typ = types.Unalias(typ)
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = types.Unalias(ptr.Elem())
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return "", false
+	}
+
+	return Sensitive(pass, named.Origin().Obj())
+}
@@ -0,0 +1,272 @@
+package annotations
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const secretsSrc = `package secrets
+
+//loglinter:sensitive
+type Token string
+
+type (
+	// Key is a signing key.
+	//loglinter:sensitive
+	Key []byte
+
+	ID string
+)
+
+type User struct {
+	Name     string
+	Pin      string ` + "`" + `loglinter:"sensitive"` + "`" + `
+	Dob      string //loglinter:sensitive
+	Internal string ` + "`" + `json:"-"` + "`" + `
+	Audit    string ` + "`" + `log:"-" json:"audit"` + "`" + `
+}
+
+// Signing returns the signing key.
+//
+//loglinter:sensitive
+func Signing() Key { return nil }
+
+func Lookup(id ID) User { return User{} }
+`
+
+const appSrc = `package app
+
+import "example.com/secrets"
+
+var (
+	_ = secrets.Token("")
+	_ = secrets.Key(nil)
+	_ = secrets.ID("")
+	_ = secrets.User{}.Pin
+	_ = secrets.Signing
+	_ = secrets.Lookup
+)
+`
+
+// factKey identifies a fact like the checker does, by object and type.
+type factKey struct {
+	obj types.Object
+	typ reflect.Type
+}
+
+// packageImporter imports packages checked earlier in a test.
+type packageImporter map[string]*types.Package
+
+func (p packageImporter) Import(path string) (*types.Package, error) {
+	return p[path], nil
+}
+
+// newPass type-checks a package with facts shared across passes.
+func newPass(t *testing.T, fset *token.FileSet, path, src string, imports packageImporter, facts map[factKey]analysis.Fact) *analysis.Pass {
+	t.Helper()
+
+	file, err := parser.ParseFile(fset, path+".go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+
+	pkg, err := (&types.Config{Importer: imports}).Check(path, fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatalf("failed to type-check %s: %v", path, err)
+	}
+	imports[path] = pkg
+
+	return &analysis.Pass{
+		Fset:      fset,
+		Files:     []*ast.File{file},
+		Pkg:       pkg,
+		TypesInfo: info,
+		ExportObjectFact: func(obj types.Object, fact analysis.Fact) {
+			if obj.Pkg() != pkg {
+				t.Errorf("fact exported for %s of another package", obj)
+			}
+			facts[factKey{obj, reflect.TypeOf(fact)}] = fact
+		},
+		ImportObjectFact: func(obj types.Object, fact analysis.Fact) bool {
+			stored, ok := facts[factKey{obj, reflect.TypeOf(fact)}]
+			if ok {
+				reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+			}
+			return ok
+		},
+	}
+}
+
+func TestExportAcrossPackages(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want map[string]string
+	}{
+		{
+			name: "default",
+			want: map[string]string{
+				"Token":   directive,
+				"Key":     directive,
+				"Pin":     `tag loglinter:"sensitive"`,
+				"Dob":     directive,
+				"Signing": directive,
+			},
+		},
+		{
+			name: "omit tags",
+			opts: Options{OmitTags: true},
+			want: map[string]string{
+				"Token":    directive,
+				"Key":      directive,
+				"Pin":      `tag loglinter:"sensitive"`,
+				"Dob":      directive,
+				"Internal": `tag json:"-"`,
+				"Audit":    `tag log:"-"`,
+				"Signing":  directive,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			imports := make(packageImporter)
+			facts := make(map[factKey]analysis.Fact)
+
+			Export(newPass(t, fset, "example.com/secrets", secretsSrc, imports, facts), tt.opts)
+
+			app := newPass(t, fset, "app", appSrc, imports, facts)
+			Export(app, tt.opts)
+
+			got := make(map[string]string)
+			for ident, obj := range app.TypesInfo.Uses {
+				if obj.Pkg() == nil || obj.Pkg().Path() != "example.com/secrets" {
+					continue
+				}
+				if reason, ok := Sensitive(app, obj); ok {
+					got[ident.Name] = reason
+				}
+			}
+
+			// fields are reached through the struct type
+			user := imports["example.com/secrets"].Scope().Lookup("User").Type().Underlying().(*types.Struct)
+			for field := range user.Fields() {
+				if reason, ok := Sensitive(app, field); ok {
+					got[field.Name()] = reason
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sensitive objects = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSensitiveType(t *testing.T) {
+	fset := token.NewFileSet()
+	imports := make(packageImporter)
+	facts := make(map[factKey]analysis.Fact)
+
+	pass := newPass(t, fset, "example.com/secrets", secretsSrc, imports, facts)
+	Export(pass, Options{})
+
+	scope := pass.Pkg.Scope()
+	tests := []struct {
+		name string
+		typ  types.Type
+		want bool
+	}{
+		{"annotated", scope.Lookup("Token").Type(), true},
+		{"pointer", types.NewPointer(scope.Lookup("Key").Type()), true},
+		{"slice of annotated", types.NewSlice(scope.Lookup("Token").Type()), false},
+		{"plain", scope.Lookup("ID").Type(), false},
+		{"struct with annotated fields", scope.Lookup("User").Type(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := SensitiveType(pass, tt.typ); ok != tt.want {
+				t.Errorf("SensitiveType(%s) = %v, want %v", tt.typ, ok, tt.want)
+			}
+		})
+	}
+}
+
+const vaultSrc = `package vault
+
+type Client struct{ addr string }
+
+type Config struct {
+	Addr       string
+	RootToken  string
+	unsealKeys []string ` + "`" + `loglinter:"sensitive"` + "`" + `
+}
+
+//loglinter:secret-source
+func (c *Client) ReadSecret(path string) string { return "" }
+
+func (c *Client) Lookup(path string) string { return "" }
+
+func (c Config) Token() string { return c.RootToken }
+
+func (c Config) Keys() []string { return c.unsealKeys }
+
+func (c Config) Address() string { return c.Addr }
+
+func Issue(c *Client) string {
+	s := c.ReadSecret("issuer")
+	return s
+}
+
+func DatabasePassword(c *Client) string { return c.ReadSecret("db") }
+
+func Wrapped(c *Client) string { return DatabasePassword(c) }
+`
+
+func TestExportSources(t *testing.T) {
+	fset := token.NewFileSet()
+	imports := make(packageImporter)
+	facts := make(map[factKey]analysis.Fact)
+
+	pass := newPass(t, fset, "example.com/vault", vaultSrc, imports, facts)
+	Export(pass, Options{
+		Sources:       []string{"(*example.com/vault.Client).Lookup"},
+		SensitiveName: func(name string) bool { return strings.Contains(strings.ToLower(name), "token") },
+	})
+
+	want := map[string]string{
+		"(*example.com/vault.Client).ReadSecret": sourceDirective,
+		"(*example.com/vault.Client).Lookup":     "configured secret source",
+		"(example.com/vault.Config).Token":       "returns field RootToken",
+		"(example.com/vault.Config).Keys":        `returns field unsealKeys (tag loglinter:"sensitive")`,
+		"example.com/vault.DatabasePassword":     "returns the result of (*example.com/vault.Client).ReadSecret",
+		"example.com/vault.Wrapped":              "returns the result of example.com/vault.DatabasePassword",
+	}
+
+	got := make(map[string]string)
+	for key, fact := range facts {
+		if fact, ok := fact.(*SourceFact); ok {
+			got[key.obj.(*types.Func).FullName()] = fact.Reason
+		}
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("secret sources = %v, want %v", got, want)
+	}
+}
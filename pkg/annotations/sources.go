@@ -0,0 +1,150 @@
+package annotations
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const sourceDirective = "//loglinter:secret-source"
+
+// SourceFact marks a function whose results are secrets, like
+// vault.ReadSecret, so that logging them is reported in any package that
+// calls it.
+type SourceFact struct {
+	// how the function was marked, like //loglinter:secret-source or
+	// "returns field Password"
+	Reason string
+}
+
+func (*SourceFact) AFact() {}
+
+func (f *SourceFact) String() string {
+	return "secret-source(" + f.Reason + ")"
+}
+
+// exportSources exports a SourceFact for every function of the package that
+// is annotated with //loglinter:secret-source, listed in opts.Sources, or
+// inferred to return a secret: an annotated field, a field opts.SensitiveName
+// matches or the result of another secret source.
+func exportSources(pass *analysis.Pass, opts Options) {
+	var funcs []*ast.FuncDecl
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			fn, ok := pass.TypesInfo.Defs[decl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+
+			switch {
+			case hasDirective(decl.Doc, sourceDirective):
+				pass.ExportObjectFact(fn, &SourceFact{Reason: sourceDirective})
+			case slices.Contains(opts.Sources, fn.FullName()):
+				pass.ExportObjectFact(fn, &SourceFact{Reason: "configured secret source"})
+			case decl.Body != nil:
+				funcs = append(funcs, decl)
+			}
+		}
+	}
+
+	// functions returning the results of others are found once those are
+	for found := true; found; {
+		found = false
+
+		for i, decl := range funcs {
+			if decl == nil {
+				continue
+			}
+			if reason := returnsSecret(pass, decl.Body, opts); reason != "" {
+				pass.ExportObjectFact(pass.TypesInfo.Defs[decl.Name], &SourceFact{Reason: reason})
+				funcs[i] = nil
+				found = true
+			}
+		}
+	}
+}
+
+// returnsSecret returns why a function body returns a secret, or "" if it
+// doesn't. Function literals within the body are skipped.
+func returnsSecret(pass *analysis.Pass, body *ast.BlockStmt, opts Options) string {
+	var reason string
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if reason != "" {
+			return false
+		}
+
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, result := range n.Results {
+				if reason = secretExpr(pass, result, opts); reason != "" {
+					break
+				}
+			}
+		}
+
+		return true
+	})
+
+	return reason
+}
+
+// secretExpr returns why a returned expression is a secret, or "" if it
+// isn't.
+func secretExpr(pass *analysis.Pass, expr ast.Expr, opts Options) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		selection, ok := pass.TypesInfo.Selections[e]
+		if !ok || selection.Kind() != types.FieldVal {
+			return ""
+		}
+
+		field := selection.Obj()
+		if reason, ok := Sensitive(pass, field); ok {
+			return fmt.Sprintf("returns field %s (%s)", field.Name(), reason)
+		}
+		if opts.SensitiveName != nil && opts.SensitiveName(field.Name()) {
+			return "returns field " + field.Name()
+		}
+
+	case *ast.CallExpr:
+		fn, ok := typeutil.Callee(pass.TypesInfo, e).(*types.Func)
+		if !ok {
+			return ""
+		}
+		if _, ok := SecretSource(pass, fn); ok {
+			return "returns the result of " + fn.FullName()
+		}
+		if _, ok := Sensitive(pass, fn); ok {
+			return "returns the result of " + fn.FullName()
+		}
+	}
+
+	return ""
+}
+
+// SecretSource reports whether a function of this or an imported package is
+// a secret source, and why.
+func SecretSource(pass *analysis.Pass, fn *types.Func) (string, bool) {
+	if fn == nil || fn.Pkg() == nil {
+		return "", false
+	}
+
+	var fact SourceFact
+	if !importFact(pass, fn.Origin(), &fact) {
+		return "", false
+	}
+
+	return fact.Reason, true
+}
@@ -0,0 +1,103 @@
+// Package compliance defines profiles of regulated data, like card numbers
+// under PCI-DSS, that log calls must not leak.
+package compliance
+
+import (
+	"fmt"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// Profile lists the keywords, types and secret detectors that identify data
+// regulated by a standard.
+type Profile struct {
+	// name used in configuration, like pci-dss
+	Name  string
+	Title string
+	// category of the data: pii, financial or health
+	Category string
+	// keywords matched against messages, attribute keys, identifiers and
+	// type names
+	Keywords []string
+	// types whose values are regulated, like net.IP
+	Types []string
+	// secret detectors finding regulated values, like card-number
+	Detectors []string
+}
+
+// Profiles are the built-in profiles.
+var Profiles = []Profile{
+	{
+		Name:     "gdpr",
+		Title:    "GDPR",
+		Category: "pii",
+		Keywords: []string{
+			"email", "email_address", "e_mail",
+			"phone", "phone_number", "mobile_number",
+			"ip_address", "client_ip", "remote_ip", "remote_addr",
+			"birth_date", "birthdate", "date_of_birth", "dob",
+			"passport", "passport_number", "national_id",
+		},
+		Types: []string{"net.IP", "net/netip.Addr", "net/mail.Address"},
+	},
+	{
+		Name:     "pci-dss",
+		Title:    "PCI-DSS",
+		Category: "financial",
+		Keywords: []string{
+			"pan", "primary_account_number",
+			"card_number", "credit_card",
+			"cvv", "cvv2", "cvc", "cvc2",
+			"track_data", "track1", "track2",
+		},
+		Detectors: []string{"card-number"},
+	},
+	{
+		Name:     "hipaa",
+		Title:    "HIPAA",
+		Category: "health",
+		Keywords: []string{
+			"diagnosis", "diagnosis_code",
+			"mrn", "medical_record", "medical_record_number",
+			"insurance_id", "health_insurance", "member_id",
+		},
+	},
+}
+
+// Lookup returns the built-in profiles with the given names.
+func Lookup(names []string) ([]Profile, error) {
+	profiles := make([]Profile, 0, len(names))
+
+	for _, name := range names {
+		i := slices.IndexFunc(Profiles, func(p Profile) bool { return p.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown compliance profile %q, want one of %s", name, strings.Join(Names(), ", "))
+		}
+		profiles = append(profiles, Profiles[i])
+	}
+
+	return profiles, nil
+}
+
+// Names lists the names of the built-in profiles.
+func Names() []string {
+	return NamesOf(Profiles)
+}
+
+// NamesOf lists the names of profiles.
+func NamesOf(profiles []Profile) []string {
+	names := make([]string, len(profiles))
+	for i, p := range profiles {
+		names[i] = p.Name
+	}
+
+	return names
+}
+
+// Evidence is a log call that may leak data regulated by a profile.
+type Evidence struct {
+	Profile  string
+	Pos, End token.Pos
+	Message  string
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 )
@@ -12,13 +13,103 @@ type Config struct {
 	Rules RulesConfig `json:"rules"`
 
 	CustomSensitivePatterns []string `json:"custom-sensitive-patterns"`
+
+	// categories of custom patterns: credential, pii, financial or health.
+	// Patterns not listed are credentials.
+	CustomSensitiveCategories map[string]string `json:"custom-sensitive-categories"`
+
+	// findings of no-sensitive-data scored below this confidence, in
+	// percent, aren't reported
+	SensitiveMinConfidence int `json:"sensitive-min-confidence"`
+
+	// severity of no-sensitive-data findings per category: error, warning,
+	// info or off
+	SensitiveSeverity map[string]string `json:"sensitive-severity"`
+
+	// phrases like "token bucket" that contain a sensitive keyword but
+	// aren't reported
+	BenignSensitivePhrases []string `json:"benign-sensitive-phrases"`
+
+	// languages whose sensitive keywords are looked for besides English:
+	// ru (Cyrillic and transliterated) or de
+	SensitiveLanguages []string `json:"sensitive-languages"`
+
+	// functions whose results are secrets, like os.Getenv("*SECRET*")
+	TaintSources []string `json:"taint-sources"`
+
+	// functions and struct fields whose values come from clients, like
+	// (*net/http.Request).FormValue, for no-log-injection
+	UntrustedSources []string `json:"untrusted-sources"`
+
+	// functions that escape line breaks, like strconv.Quote, besides the
+	// ones no-log-injection knows
+	LogSanitizers []string `json:"log-sanitizers"`
+
+	// functions forwarding a printf format and its arguments to a logger,
+	// like (*example.com/app/log.Logger).Debugf, checked as logging methods
+	PrintfWrappers []string `json:"printf-wrappers"`
+
+	// functions forwarding a message and slog key/value pairs to a logger,
+	// like (*example.com/app/log.Logger).Info, checked as logging methods
+	SlogWrappers []string `json:"slog-wrappers"`
+
+	// naming convention of attribute keys for attr-key-style: snake_case,
+	// camelCase or dotted
+	KeyStyle string `json:"key-style"`
+
+	// path of a JSON file listing the allowed attribute keys and the kinds
+	// of their values, for attr-key-schema, relative to the config file
+	KeySchema string `json:"key-schema"`
+
+	// release of the OpenTelemetry semantic conventions otel-semconv
+	// suggests keys of, like 1.26.0, the latest bundled one by default
+	SemconvVersion string `json:"semconv-version"`
+
+	// also require constant messages from the log package and print- and
+	// printf-style methods, which no-dynamic-message exempts by default
+	DynamicMessageUnstructured bool `json:"dynamic-message-unstructured"`
+
+	// how many levels of nested structs no-sensitive-structs searches
+	SensitiveStructDepth int `json:"sensitive-struct-depth"`
+
+	// treat struct fields tagged log:"-" or json:"-" as sensitive
+	SensitiveOmitTags bool `json:"sensitive-omit-tags"`
+
+	SecretDetectors SecretDetectorsConfig `json:"secret-detectors"`
+
+	// compliance profiles whose regulated data no-sensitive-data looks
+	// for: gdpr, pci-dss or hipaa
+	Compliance []string `json:"compliance"`
+}
+
+// SecretDetectorsConfig tunes detection of secret values like tokens and
+// card numbers written into log calls.
+type SecretDetectorsConfig struct {
+	// detectors to turn off, like "entropy" or "iban"
+	Disabled []string `json:"disabled"`
+	// bits per character above which a word counts as random
+	EntropyThreshold float64 `json:"entropy-threshold"`
+	// words shorter than this aren't checked for entropy
+	EntropyMinLength int `json:"entropy-min-length"`
 }
 
 type RulesConfig struct {
-	LowercaseStart   RuleConfig `json:"lowercase-start"`
-	EnglishOnly      RuleConfig `json:"english-only"`
-	NoSpecialSymbols RuleConfig `json:"no-special-symbols"`
-	NoSensitiveData  RuleConfig `json:"no-sensitive-data"`
+	LowercaseStart     RuleConfig `json:"lowercase-start"`
+	EnglishOnly        RuleConfig `json:"english-only"`
+	NoSpecialSymbols   RuleConfig `json:"no-special-symbols"`
+	NoSensitiveData    RuleConfig `json:"no-sensitive-data"`
+	NoSensitiveStructs RuleConfig `json:"no-sensitive-structs"`
+	NoSensitiveTypes   RuleConfig `json:"no-sensitive-types"`
+	NoSensitiveMethods RuleConfig `json:"no-sensitive-methods"`
+	NoLogInjection     RuleConfig `json:"no-log-injection"`
+	PrintfFormat       RuleConfig `json:"printf-format"`
+	NoDynamicMessage   RuleConfig `json:"no-dynamic-message"`
+	NoPrintfVerbs      RuleConfig `json:"no-printf-verbs"`
+	SlogKeyValue       RuleConfig `json:"slog-key-value"`
+	AttrKeyStyle       RuleConfig `json:"attr-key-style"`
+	AttrKeySchema      RuleConfig `json:"attr-key-schema"`
+	OtelSemconv        RuleConfig `json:"otel-semconv"`
+	TypedAttrs         RuleConfig `json:"typed-attrs"`
 }
 
 type RuleConfig struct {
@@ -29,12 +120,26 @@ func DefaultConfig() *Config {
 	return &Config{
 		Enabled: true,
 		Rules: RulesConfig{
-			LowercaseStart:   RuleConfig{Enabled: true},
-			EnglishOnly:      RuleConfig{Enabled: true},
-			NoSpecialSymbols: RuleConfig{Enabled: true},
-			NoSensitiveData:  RuleConfig{Enabled: true},
+			LowercaseStart:     RuleConfig{Enabled: true},
+			EnglishOnly:        RuleConfig{Enabled: true},
+			NoSpecialSymbols:   RuleConfig{Enabled: true},
+			NoSensitiveData:    RuleConfig{Enabled: true},
+			NoSensitiveStructs: RuleConfig{Enabled: true},
+			NoSensitiveTypes:   RuleConfig{Enabled: true},
+			NoSensitiveMethods: RuleConfig{Enabled: true},
+			NoLogInjection:     RuleConfig{Enabled: true},
+			PrintfFormat:       RuleConfig{Enabled: true},
+			NoDynamicMessage:   RuleConfig{Enabled: false},
+			NoPrintfVerbs:      RuleConfig{Enabled: true},
+			SlogKeyValue:       RuleConfig{Enabled: true},
+			AttrKeyStyle:       RuleConfig{Enabled: false},
+			AttrKeySchema:      RuleConfig{Enabled: false},
+			OtelSemconv:        RuleConfig{Enabled: false},
+			TypedAttrs:         RuleConfig{Enabled: false},
 		},
 		CustomSensitivePatterns: []string{},
+		SensitiveStructDepth:    3,
+		KeyStyle:                "snake_case",
 	}
 }
 
@@ -58,9 +163,39 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	// the key schema sits next to the config, wherever the linter runs
+	if cfg.KeySchema != "" && !filepath.IsAbs(cfg.KeySchema) {
+		cfg.KeySchema = filepath.Join(filepath.Dir(path), cfg.KeySchema)
+	}
+
 	return cfg, nil
 }
 
+// KeySchema lists the allowed attribute keys and the kinds of their values,
+// like {"keys": {"user_id": "string", "duration_ms": "int"}}.
+type KeySchema struct {
+	Keys map[string]string `json:"keys"`
+}
+
+// LoadKeySchema reads a key schema file. An empty path means no schema.
+func LoadKeySchema(path string) (*KeySchema, error) {
+	if path == "" {
+		return &KeySchema{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &KeySchema{}
+	if err := json.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("key schema %s: %w", path, err)
+	}
+
+	return schema, nil
+}
+
 func SaveConfig(cfg *Config, path string) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -102,6 +237,42 @@ func (c *Config) GetEnabledRules() []string {
 	if c.Rules.NoSensitiveData.Enabled {
 		enabled = append(enabled, "no-sensitive-data")
 	}
+	if c.Rules.NoSensitiveStructs.Enabled {
+		enabled = append(enabled, "no-sensitive-structs")
+	}
+	if c.Rules.NoSensitiveTypes.Enabled {
+		enabled = append(enabled, "no-sensitive-types")
+	}
+	if c.Rules.NoSensitiveMethods.Enabled {
+		enabled = append(enabled, "no-sensitive-methods")
+	}
+	if c.Rules.NoLogInjection.Enabled {
+		enabled = append(enabled, "no-log-injection")
+	}
+	if c.Rules.PrintfFormat.Enabled {
+		enabled = append(enabled, "printf-format")
+	}
+	if c.Rules.NoDynamicMessage.Enabled {
+		enabled = append(enabled, "no-dynamic-message")
+	}
+	if c.Rules.NoPrintfVerbs.Enabled {
+		enabled = append(enabled, "no-printf-verbs")
+	}
+	if c.Rules.SlogKeyValue.Enabled {
+		enabled = append(enabled, "slog-key-value")
+	}
+	if c.Rules.AttrKeyStyle.Enabled {
+		enabled = append(enabled, "attr-key-style")
+	}
+	if c.Rules.AttrKeySchema.Enabled {
+		enabled = append(enabled, "attr-key-schema")
+	}
+	if c.Rules.OtelSemconv.Enabled {
+		enabled = append(enabled, "otel-semconv")
+	}
+	if c.Rules.TypedAttrs.Enabled {
+		enabled = append(enabled, "typed-attrs")
+	}
 
 	return enabled
 }
@@ -125,6 +296,42 @@ func (c *Config) GetDisabledRules() []string {
 	if !c.Rules.NoSensitiveData.Enabled {
 		disabled = append(disabled, "no-sensitive-data")
 	}
+	if !c.Rules.NoSensitiveStructs.Enabled {
+		disabled = append(disabled, "no-sensitive-structs")
+	}
+	if !c.Rules.NoSensitiveTypes.Enabled {
+		disabled = append(disabled, "no-sensitive-types")
+	}
+	if !c.Rules.NoSensitiveMethods.Enabled {
+		disabled = append(disabled, "no-sensitive-methods")
+	}
+	if !c.Rules.NoLogInjection.Enabled {
+		disabled = append(disabled, "no-log-injection")
+	}
+	if !c.Rules.PrintfFormat.Enabled {
+		disabled = append(disabled, "printf-format")
+	}
+	if !c.Rules.NoDynamicMessage.Enabled {
+		disabled = append(disabled, "no-dynamic-message")
+	}
+	if !c.Rules.NoPrintfVerbs.Enabled {
+		disabled = append(disabled, "no-printf-verbs")
+	}
+	if !c.Rules.SlogKeyValue.Enabled {
+		disabled = append(disabled, "slog-key-value")
+	}
+	if !c.Rules.AttrKeyStyle.Enabled {
+		disabled = append(disabled, "attr-key-style")
+	}
+	if !c.Rules.AttrKeySchema.Enabled {
+		disabled = append(disabled, "attr-key-schema")
+	}
+	if !c.Rules.OtelSemconv.Enabled {
+		disabled = append(disabled, "otel-semconv")
+	}
+	if !c.Rules.TypedAttrs.Enabled {
+		disabled = append(disabled, "typed-attrs")
+	}
 
 	return disabled
 }
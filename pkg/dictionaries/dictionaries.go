@@ -0,0 +1,118 @@
+// Package dictionaries holds sensitive keywords in languages other than
+// English, like parol and пароль for password.
+package dictionaries
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Dictionary lists the sensitive keywords of a language per category.
+type Dictionary struct {
+	// code used in configuration, like ru
+	Language string
+	Name     string
+	Keywords []Keywords
+}
+
+// Keywords are words of a category: credential, pii, financial or health.
+type Keywords struct {
+	Category string
+	Words    []string
+}
+
+// Dictionaries are the built-in dictionaries.
+var Dictionaries = []Dictionary{
+	{
+		Language: "ru",
+		Name:     "Russian",
+		Keywords: []Keywords{
+			{
+				Category: "credential",
+				Words: []string{
+					"пароль", "токен", "секрет", "секретный ключ", "ключ api", "учетные данные",
+					"parol", "sekret", "secret_klyuch", "klyuch_api", "uchetnye_dannye",
+				},
+			},
+			{
+				Category: "pii",
+				Words: []string{
+					"паспорт", "снилс", "инн", "дата рождения",
+					"pasport", "snils", "inn", "data_rozhdeniya",
+				},
+			},
+			{
+				Category: "financial",
+				Words: []string{
+					"номер карты", "кредитная карта",
+					"nomer_karty", "kreditnaya_karta",
+				},
+			},
+			{
+				Category: "health",
+				Words: []string{
+					"диагноз", "медицинская карта",
+					"diagnoz", "medkarta",
+				},
+			},
+		},
+	},
+	{
+		Language: "de",
+		Name:     "German",
+		Keywords: []Keywords{
+			{
+				Category: "credential",
+				Words: []string{
+					"passwort", "kennwort", "geheimnis", "zugangsdaten",
+					"api_schlüssel", "geheimer_schlüssel", "api_schluessel", "geheimer_schluessel",
+				},
+			},
+			{
+				Category: "pii",
+				Words: []string{
+					"geburtsdatum", "personalausweis", "reisepass",
+					"sozialversicherungsnummer", "steuer_id", "steueridentifikationsnummer",
+				},
+			},
+			{
+				Category: "financial",
+				Words: []string{
+					"kreditkarte", "kartennummer", "kontonummer", "bankverbindung",
+				},
+			},
+			{
+				Category: "health",
+				Words: []string{
+					"diagnose", "krankenakte", "krankenversicherungsnummer",
+				},
+			},
+		},
+	},
+}
+
+// Lookup returns the built-in dictionaries of the given languages.
+func Lookup(languages []string) ([]Dictionary, error) {
+	dicts := make([]Dictionary, 0, len(languages))
+
+	for _, language := range languages {
+		i := slices.IndexFunc(Dictionaries, func(d Dictionary) bool { return d.Language == language })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown sensitive keyword language %q, want one of %s", language, strings.Join(Languages(), ", "))
+		}
+		dicts = append(dicts, Dictionaries[i])
+	}
+
+	return dicts, nil
+}
+
+// Languages lists the languages of the built-in dictionaries.
+func Languages() []string {
+	languages := make([]string, len(Dictionaries))
+	for i, d := range Dictionaries {
+		languages[i] = d.Language
+	}
+
+	return languages
+}
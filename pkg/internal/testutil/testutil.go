@@ -0,0 +1,110 @@
+// Package testutil type-checks the sources of rule and detector tests
+// against stand-ins for third-party loggers.
+package testutil
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// stubImporter type-checks stand-ins for third-party loggers from
+// testdata/stubs and imports everything else from source.
+type stubImporter struct {
+	fset   *token.FileSet
+	source types.Importer
+	stubs  map[string]*types.Package
+}
+
+func (i *stubImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := i.stubs[path]; ok {
+		return pkg, nil
+	}
+
+	dir := filepath.Join(stubsDir(), filepath.FromSlash(path))
+	if _, err := os.Stat(dir); err != nil {
+		return i.source.Import(path)
+	}
+
+	pkgs, err := parser.ParseDir(i.fset, dir, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+
+	pkg, err := (&types.Config{Importer: i}).Check(path, i.fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	i.stubs[path] = pkg
+	return pkg, nil
+}
+
+// stubsDir returns testdata/stubs at the root of the module.
+func stubsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "..", "testdata", "stubs")
+}
+
+// Fset is the file set of the sources passes are built from.
+var Fset = token.NewFileSet()
+
+// imported packages are shared between tests, type-checking them from
+// source takes a while
+var imported = &stubImporter{
+	fset:   Fset,
+	source: importer.ForCompiler(Fset, "source", nil),
+	stubs:  make(map[string]*types.Package),
+}
+
+// NewPass type-checks src as package test and returns a pass over it.
+func NewPass(t *testing.T, src string) (*analysis.Pass, *ast.File) {
+	t.Helper()
+
+	file, err := parser.ParseFile(Fset, "test.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("failed to parse source: %v", err)
+	}
+
+	info := &types.Info{
+		Types:        make(map[ast.Expr]types.TypeAndValue),
+		Defs:         make(map[*ast.Ident]types.Object),
+		Uses:         make(map[*ast.Ident]types.Object),
+		Implicits:    make(map[ast.Node]types.Object),
+		Selections:   make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:       make(map[ast.Node]*types.Scope),
+		Instances:    make(map[*ast.Ident]types.Instance),
+		FileVersions: make(map[*ast.File]string),
+	}
+
+	conf := &types.Config{Importer: imported}
+
+	pkg, err := conf.Check("test", Fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatalf("failed to type-check source: %v", err)
+	}
+
+	readFile := func(name string) ([]byte, error) {
+		if name != "test.go" {
+			return os.ReadFile(name)
+		}
+		return []byte(src), nil
+	}
+
+	return &analysis.Pass{Fset: Fset, Files: []*ast.File{file}, Pkg: pkg, TypesInfo: info, ReadFile: readFile}, file
+}
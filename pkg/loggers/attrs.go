@@ -0,0 +1,351 @@
+package loggers
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+)
+
+// Attr is a structured attribute of a log call: a slog key/value pair or
+// slog.Attr, a zap.Field, a key/value pair of a sugared ...w method, a
+// field added to a zerolog event, or a logrus field.
+type Attr struct {
+	// constant key, empty if the key is not a constant
+	Key string
+	// key expression, nil if the key is implied (zap.Error) or missing
+	KeyExpr ast.Expr
+	// value expression, nil if the key has no value
+	Value ast.Expr
+	// attribute constructor like slog.String or zap.Int, zerolog field
+	// method like Str, or logrus WithField, nil for key/value pairs, entries
+	// of logrus.Fields and attributes that aren't built in place
+	Func *types.Func
+	// the constructor call, or the first expression of a key/value pair
+	Expr ast.Expr
+	// attributes nested under slog.Group, zap.Dict or zerolog's Dict
+	Group []Attr
+	// attached by a With call on the logger rather than by the call itself
+	FromWith bool
+}
+
+// IsPair reports whether the attribute is a key/value pair of a variadic
+// args list, or an entry of a logrus.Fields literal, rather than an
+// attribute value.
+func (a Attr) IsPair() bool {
+	return a.Func == nil && a.KeyExpr != nil && a.KeyExpr == a.Expr
+}
+
+func (d *Detector) parseAttrs(loggerType LoggerType, method string, args []ast.Expr) []Attr {
+	switch loggerType {
+	case SlogLogger:
+		if method == "LogAttrs" {
+			return d.parseAttrValues(args)
+		}
+		return d.parsePairs(args)
+	case ZapLogger:
+		return d.parseAttrValues(args)
+	default:
+		return d.parsePairs(args)
+	}
+}
+
+// WithCall is a With call adding attributes to a logger, like
+// slog.With("service", "api"), logger.With(zap.Int("port", 8080)) or
+// logrus.WithField("service", "api").
+type WithCall struct {
+	Call   *ast.CallExpr
+	Logger LoggerType
+	// attributes of the call, and of With calls on its receiver, marked
+	// FromWith
+	Attrs []Attr
+}
+
+// DetectWithCalls returns the With calls of a file, whether or not the
+// logger they return is used to log in the file.
+func (d *Detector) DetectWithCalls(file *ast.File) []WithCall {
+	var withCalls []WithCall
+
+	if d.pass == nil || d.pass.TypesInfo == nil {
+		return withCalls
+	}
+
+	d.loggers = d.collectLoggerValues(file)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		fn := d.funcOf(call.Fun)
+		if fn == nil || !isWith(fn) {
+			return true
+		}
+
+		withCall := WithCall{Call: call, Logger: LoggerTypeOf(fn)}
+		if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+			withCall.Attrs = d.withAttrs(sel.X, nil)
+		}
+		if !call.Ellipsis.IsValid() {
+			withCall.Attrs = append(withCall.Attrs, d.parseWithArgs(fn, call)...)
+		}
+		withCalls = append(withCalls, withCall)
+
+		return true
+	})
+
+	return withCalls
+}
+
+// withAttrs collects attributes attached by With calls along a receiver
+// chain, like slog.With("a", 1).With("b", 2) or zap.L().With(zap.Int("a", 1)),
+// following local variables holding a logger, like l in l :=
+// slog.With("a", 1).
+func (d *Detector) withAttrs(recv ast.Expr, seen map[*types.Var]bool) []Attr {
+	if ident, ok := ast.Unparen(recv).(*ast.Ident); ok {
+		obj, ok := d.pass.TypesInfo.Uses[ident].(*types.Var)
+		if !ok || seen[obj] || d.loggers[obj] == nil {
+			return nil
+		}
+		if seen == nil {
+			seen = make(map[*types.Var]bool)
+		}
+		seen[obj] = true
+		return d.withAttrs(d.loggers[obj], seen)
+	}
+
+	call, ok := ast.Unparen(recv).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	fn := d.funcOf(call.Fun)
+	if fn == nil || LoggerTypeOf(fn) == UnknownLogger {
+		return nil
+	}
+
+	var attrs []Attr
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		attrs = d.withAttrs(sel.X, seen)
+	}
+
+	if !isWith(fn) || call.Ellipsis.IsValid() {
+		return attrs
+	}
+
+	with := d.parseWithArgs(fn, call)
+	for i := range with {
+		with[i].FromWith = true
+	}
+
+	return append(attrs, with...)
+}
+
+// isWith reports whether fn returns a logger with attributes added, like
+// With, or logrus's WithField.
+func isWith(fn *types.Func) bool {
+	switch fn.Name() {
+	case "With":
+		return LoggerTypeOf(fn) != UnknownLogger
+	case "WithField", "WithFields", "WithError":
+		return LoggerTypeOf(fn) == LogrusLogger
+	}
+
+	return false
+}
+
+// parseWithArgs parses the arguments of a With call: zap fields, logrus
+// fields, or pairs for slog and sugared loggers.
+func (d *Detector) parseWithArgs(fn *types.Func, call *ast.CallExpr) []Attr {
+	switch LoggerTypeOf(fn) {
+	case ZapLogger:
+		return d.parseAttrValues(call.Args)
+	case LogrusLogger:
+		return d.parseLogrusFields(fn, call)
+	}
+
+	return d.parsePairs(call.Args)
+}
+
+// parseLogrusFields parses the fields added by WithField, WithFields with a
+// logrus.Fields literal, or WithError, which adds the "error" field.
+func (d *Detector) parseLogrusFields(fn *types.Func, call *ast.CallExpr) []Attr {
+	switch {
+	case fn.Name() == "WithField" && len(call.Args) == 2:
+		return []Attr{{
+			Key:     d.constString(call.Args[0]),
+			KeyExpr: call.Args[0],
+			Value:   call.Args[1],
+			Func:    fn,
+			Expr:    call,
+		}}
+	case fn.Name() == "WithError" && len(call.Args) == 1:
+		return []Attr{{Key: "error", Value: call.Args[0], Func: fn, Expr: call}}
+	case fn.Name() == "WithFields" && len(call.Args) == 1:
+		lit, ok := ast.Unparen(call.Args[0]).(*ast.CompositeLit)
+		if !ok {
+			return nil
+		}
+		var attrs []Attr
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				attrs = append(attrs, Attr{Key: d.constString(kv.Key), KeyExpr: kv.Key, Value: kv.Value, Expr: kv.Key})
+			}
+		}
+		return attrs
+	}
+
+	return nil
+}
+
+// parsePairs parses variadic args ...any, where attributes are either
+// key/value pairs or attribute values.
+func (d *Detector) parsePairs(args []ast.Expr) []Attr {
+	var attrs []Attr
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		typ := d.pass.TypesInfo.TypeOf(arg)
+
+		if isAttrType(typ) {
+			attrs = append(attrs, d.parseAttrValue(arg))
+			continue
+		}
+
+		if typ == nil || !isString(typ) {
+			attrs = append(attrs, Attr{Value: arg, Expr: arg})
+			continue
+		}
+
+		attr := Attr{
+			Key:     d.constString(arg),
+			KeyExpr: arg,
+			Expr:    arg,
+		}
+		if i+1 < len(args) {
+			attr.Value = args[i+1]
+			i++
+		}
+		attrs = append(attrs, attr)
+	}
+
+	return attrs
+}
+
+func (d *Detector) parseAttrValues(args []ast.Expr) []Attr {
+	attrs := make([]Attr, 0, len(args))
+
+	for _, arg := range args {
+		attrs = append(attrs, d.parseAttrValue(arg))
+	}
+
+	return attrs
+}
+
+// parseAttrValue parses a slog.Attr or zap.Field expression. Only attributes
+// built in place by a constructor of slog or zap have a known key.
+func (d *Detector) parseAttrValue(expr ast.Expr) Attr {
+	attr := Attr{Value: expr, Expr: expr}
+
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return attr
+	}
+
+	fn := d.funcOf(call.Fun)
+	if fn == nil || !isAttrConstructor(fn) {
+		return attr
+	}
+
+	attr.Func = fn
+	attr.Value = nil
+
+	switch fn.Name() {
+	case "Group":
+		if len(call.Args) > 0 {
+			attr.KeyExpr, attr.Key = call.Args[0], d.constString(call.Args[0])
+			attr.Group = d.parsePairs(call.Args[1:])
+		}
+		return attr
+	case "GroupAttrs", "Dict":
+		if len(call.Args) > 0 {
+			attr.KeyExpr, attr.Key = call.Args[0], d.constString(call.Args[0])
+			attr.Group = d.parseAttrValues(call.Args[1:])
+		}
+		return attr
+	case "Error":
+		// zap.Error(err) logs under the "error" key
+		attr.Key = "error"
+		if len(call.Args) > 0 {
+			attr.Value = call.Args[0]
+		}
+		return attr
+	}
+
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() > 0 && isString(sig.Params().At(0).Type()) && len(call.Args) > 0 {
+		attr.KeyExpr, attr.Key = call.Args[0], d.constString(call.Args[0])
+		if len(call.Args) > 1 {
+			attr.Value = call.Args[1]
+		}
+		return attr
+	}
+
+	if len(call.Args) > 0 {
+		attr.Value = call.Args[0]
+	}
+
+	return attr
+}
+
+// funcOf returns the function or method an expression refers to.
+func (d *Detector) funcOf(expr ast.Expr) *types.Func {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		if selection, ok := d.pass.TypesInfo.Selections[e]; ok {
+			fn, _ := selection.Obj().(*types.Func)
+			return fn
+		}
+		fn, _ := d.pass.TypesInfo.Uses[e.Sel].(*types.Func)
+		return fn
+	case *ast.Ident:
+		fn, _ := d.pass.TypesInfo.Uses[e].(*types.Func)
+		return fn
+	case *ast.IndexExpr:
+		// instantiated generic function
+		return d.funcOf(e.X)
+	}
+
+	return nil
+}
+
+func (d *Detector) constString(expr ast.Expr) string {
+	tv, ok := d.pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return ""
+	}
+
+	return constant.StringVal(tv.Value)
+}
+
+// isAttrConstructor reports whether fn is a package-level function of slog
+// or zap that returns an attribute.
+func isAttrConstructor(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() != nil || fn.Pkg() == nil || sig.Results().Len() != 1 {
+		return false
+	}
+
+	switch fn.Pkg().Path() {
+	case "log/slog", "go.uber.org/zap":
+		return isAttrType(sig.Results().At(0).Type())
+	}
+
+	return false
+}
+
+func isAttrType(typ types.Type) bool {
+	return isNamed(typ, "log/slog", "Attr") ||
+		isNamed(typ, "go.uber.org/zap/zapcore", "Field") ||
+		isNamed(typ, "go.uber.org/zap", "Field")
+}
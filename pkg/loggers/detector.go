@@ -2,15 +2,25 @@ package loggers
 
 import (
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
-	"strings"
+	"strconv"
 
 	"golang.org/x/tools/go/analysis"
 )
 
 type Detector struct {
 	pass *analysis.Pass
+	// logging functions held by local variables of the current file
+	values funcValues
+	// logging functions held by struct fields of the package
+	fields funcValues
+	// local variables of the current file holding loggers
+	loggers loggerValues
+	// full names of configured printf and slog wrappers
+	wrappers     map[string]bool
+	slogWrappers map[string]bool
 }
 
 func NewDetector(pass *analysis.Pass) *Detector {
@@ -24,6 +34,19 @@ type LogCall struct {
 	Message ast.Expr
 	Logger  LoggerType
 	Method  string
+	Spec    MethodSpec
+
+	// normalized level, resolved from the level argument of Log-style methods
+	Level Level
+	// context.Context argument, nil if the method takes none
+	Context ast.Expr
+	// message is a printf format and FormatArgs are its arguments
+	Format     bool
+	FormatArgs []ast.Expr
+	// structured attributes of the call and of With calls on its logger
+	Attrs []Attr
+	// resolved text of the message
+	Text Message
 }
 
 func (d *Detector) DetectLogCalls(file *ast.File) []LogCall {
@@ -33,6 +56,12 @@ func (d *Detector) DetectLogCalls(file *ast.File) []LogCall {
 		return logCalls
 	}
 
+	if d.fields == nil {
+		d.fields = d.collectFieldValues()
+	}
+	d.values = d.collectFuncValues(file)
+	d.loggers = d.collectLoggerValues(file)
+
 	ast.Inspect(file, func(n ast.Node) bool {
 		callExpr, ok := n.(*ast.CallExpr)
 		if !ok {
@@ -51,106 +80,329 @@ func (d *Detector) DetectLogCalls(file *ast.File) []LogCall {
 }
 
 func (d *Detector) analyzeCallExpr(call *ast.CallExpr) *LogCall {
-	selectorExp, ok := call.Fun.(*ast.SelectorExpr)
-	if !ok {
+	target := d.resolveFunc(call.Fun)
+	if target == nil {
 		return nil
 	}
 
-	method := selectorExp.Sel.Name
-
-	if !isLogMethod(method) {
+	if len(call.Args) <= target.spec.MessageIndex {
 		return nil
 	}
 
-	loggerType := d.loggerType(selectorExp.X)
-	if loggerType == UnknownLogger {
-		return nil
+	logCall := &LogCall{
+		Call:    call,
+		Message: call.Args[target.spec.MessageIndex],
+		Logger:  target.logger,
+		Method:  target.method,
+		Spec:    target.spec,
+		Level:   target.spec.Level,
+		Format:  target.spec.Printf,
 	}
 
-	if len(call.Args) == 0 {
-		return nil
+	d.describeArgs(logCall)
+
+	return logCall
+}
+
+// describeArgs fills in the parts of a log call that depend on its arguments.
+func (d *Detector) describeArgs(logCall *LogCall) {
+	spec := logCall.Spec
+	args := logCall.Call.Args
+
+	for _, arg := range args[:spec.MessageIndex] {
+		if isContext(d.pass.TypesInfo.TypeOf(arg)) {
+			logCall.Context = arg
+			break
+		}
 	}
 
-	return &LogCall{
-		Call:    call,
-		Message: call.Args[0],
-		Logger:  loggerType,
-		Method:  method,
+	if logCall.Level == LevelUnknown && spec.MessageIndex > 0 {
+		logCall.Level = d.constLevel(logCall.Logger, args[spec.MessageIndex-1])
 	}
-}
 
-func isLogMethod(method string) bool {
-	_, ok := logMethods[method]
-	return ok
-}
+	logCall.Text = d.buildMessage(logCall.Message)
 
-func (d *Detector) loggerType(expr ast.Expr) LoggerType {
-	if d.pass == nil || d.pass.TypesInfo == nil {
-		return UnknownLogger
+	if spec.Printf {
+		logCall.FormatArgs = args[spec.MessageIndex+1:]
+		logCall.Text.Segments = applyFormat(logCall.Text.Segments, logCall.FormatArgs)
 	}
 
-	if ident, ok := expr.(*ast.Ident); ok {
-		return d.identifyByIdent(ident)
+	if sel, ok := ast.Unparen(logCall.Call.Fun).(*ast.SelectorExpr); ok {
+		if logCall.Logger == ZerologLogger {
+			d.describeEvent(logCall, sel.X)
+		} else {
+			logCall.Attrs = append(logCall.Attrs, d.withAttrs(sel.X, nil)...)
+		}
 	}
 
-	return d.identifyByType(expr)
+	// attributes spread from a slice can't be told apart
+	if spec.AttrsIndex >= 0 && spec.AttrsIndex <= len(args) && !logCall.Call.Ellipsis.IsValid() {
+		logCall.Attrs = append(logCall.Attrs, d.parseAttrs(logCall.Logger, logCall.Method, args[spec.AttrsIndex:])...)
+	}
 }
 
-func (d *Detector) identifyByIdent(ident *ast.Ident) LoggerType {
-	obj := d.pass.TypesInfo.ObjectOf(ident)
-	if obj == nil {
-		return UnknownLogger
+// constLevel normalizes a constant level argument of slog, zap, zerolog or
+// logrus.
+func (d *Detector) constLevel(loggerType LoggerType, expr ast.Expr) Level {
+	tv, ok := d.pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil {
+		return LevelUnknown
 	}
 
-	if pkgName, ok := obj.(*types.PkgName); ok {
-		pkgPath := pkgName.Imported().Path()
+	value, ok := constant.Int64Val(constant.ToInt(tv.Value))
+	if !ok {
+		return LevelUnknown
+	}
 
-		switch pkgPath {
-		case "log/slog":
-			return SlogLogger
-		case "go.uber.org/zap":
-			return ZapLogger
-		case "log":
-			return LogLogger
+	switch loggerType {
+	case SlogLogger:
+		switch {
+		case value < 0:
+			return LevelDebug
+		case value < 4:
+			return LevelInfo
+		case value < 8:
+			return LevelWarn
 		default:
-			return UnknownLogger
+			return LevelError
+		}
+	case ZerologLogger:
+		switch {
+		case value <= 0:
+			return LevelDebug
+		case value == 1:
+			return LevelInfo
+		case value == 2:
+			return LevelWarn
+		case value == 3:
+			return LevelError
+		case value == 4:
+			return LevelFatal
+		case value == 5:
+			return LevelPanic
+		}
+	case LogrusLogger:
+		switch value {
+		case 0:
+			return LevelPanic
+		case 1:
+			return LevelFatal
+		case 2:
+			return LevelError
+		case 3:
+			return LevelWarn
+		case 4:
+			return LevelInfo
+		default:
+			return LevelDebug
+		}
+	case ZapLogger, ZapSugarLogger:
+		switch {
+		case value < 0:
+			return LevelDebug
+		case value == 0:
+			return LevelInfo
+		case value == 1:
+			return LevelWarn
+		case value == 2:
+			return LevelError
+		case value < 5:
+			return LevelPanic
+		default:
+			return LevelFatal
+		}
+	}
+
+	return LevelUnknown
+}
+
+func isContext(typ types.Type) bool {
+	return isNamed(typ, "context", "Context")
+}
+
+// isNamed reports whether typ, after resolving aliases and pointers, is the
+// named type pkgPath.name.
+func isNamed(typ types.Type, pkgPath, name string) bool {
+	if typ == nil {
+		return false
+	}
+
+	typ = types.Unalias(typ)
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = types.Unalias(ptr.Elem())
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Origin().Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgPath && obj.Name() == name
+}
+
+// resolveFunc resolves the function part of a call to a logging method. It
+// follows selectors and identifiers to the function they name, and local
+// variables and struct fields to the logging functions assigned to them.
+func (d *Detector) resolveFunc(expr ast.Expr) *logFunc {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		if selection, ok := d.pass.TypesInfo.Selections[e]; ok {
+			switch obj := selection.Obj().(type) {
+			case *types.Func:
+				return d.newLogFunc(obj, selection.Kind() == types.MethodExpr)
+			case *types.Var:
+				return d.fields.lookup(obj, false)
+			}
+			return nil
+		}
+		return d.resolveObject(d.pass.TypesInfo.Uses[e.Sel])
+
+	case *ast.Ident:
+		return d.resolveObject(d.pass.TypesInfo.Uses[e])
+
+	case *ast.IndexExpr:
+		switch x := ast.Unparen(e.X).(type) {
+		case *ast.Ident:
+			if v, ok := d.pass.TypesInfo.Uses[x].(*types.Var); ok {
+				return d.values.lookup(v, true)
+			}
+		case *ast.SelectorExpr:
+			if selection, ok := d.pass.TypesInfo.Selections[x]; ok && selection.Kind() == types.FieldVal {
+				return d.fields.lookup(selection.Obj().(*types.Var), true)
+			}
 		}
 	}
 
-	return d.identifyByType(ident)
+	return nil
 }
 
-func (d *Detector) identifyByType(expr ast.Expr) LoggerType {
-	logType := d.pass.TypesInfo.TypeOf(expr)
-	if logType == nil {
+func (d *Detector) resolveObject(obj types.Object) *logFunc {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return d.newLogFunc(obj, false)
+	case *types.Var:
+		return d.values.lookup(obj, false)
+	}
+
+	return nil
+}
+
+// LoggerTypeOf reports which logger a function or method belongs to.
+func LoggerTypeOf(fn *types.Func) LoggerType {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok {
+		return UnknownLogger
+	}
+
+	recv := sig.Recv()
+	if recv == nil {
+		if fn.Pkg() == nil {
+			return UnknownLogger
+		}
+		if loggerType, ok := packageLoggers[fn.Pkg().Path()]; ok {
+			return loggerType
+		}
 		return UnknownLogger
 	}
 
-	typStr := logType.String()
+	return receiverLoggerType(recv.Type(), sig)
+}
+
+func receiverLoggerType(typ types.Type, sig *types.Signature) LoggerType {
+	typ = types.Unalias(typ)
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = types.Unalias(ptr.Elem())
+	}
 
-	for _, check := range loggerChecks {
-		if strings.Contains(typStr, check.pattern) {
-			return check.logType
+	switch t := typ.(type) {
+	case *types.Named:
+		obj := t.Origin().Obj()
+		if obj.Pkg() != nil {
+			if loggerType, ok := namedLoggers[loggerName{obj.Pkg().Path(), obj.Name()}]; ok {
+				return loggerType
+			}
+		}
+		if iface, ok := t.Underlying().(*types.Interface); ok {
+			return interfaceLoggerType(iface, sig)
 		}
+	case *types.Interface:
+		return interfaceLoggerType(t, sig)
 	}
 
 	return UnknownLogger
 }
 
+// interfaceLoggerType accepts methods shaped like a logging call of
+// interfaces that look like a logger: their logging methods have several
+// levels, one of them info or debug. testing.TB, with only Errorf and
+// Fatalf, isn't a logger.
+func interfaceLoggerType(iface *types.Interface, sig *types.Signature) LoggerType {
+	if !loggingSignature(sig) {
+		return UnknownLogger
+	}
+
+	levels := make(map[Level]bool)
+	for method := range iface.Methods() {
+		spec, ok := LookupMethod(InterfaceLogger, method.Name())
+		if ok && loggingSignature(method.Signature()) {
+			levels[spec.Level] = true
+		}
+	}
+
+	if len(levels) < 2 || !(levels[LevelDebug] || levels[LevelInfo]) {
+		return UnknownLogger
+	}
+
+	return InterfaceLogger
+}
+
+// loggingSignature reports whether a method takes a string message,
+// optionally followed by variadic arguments.
+func loggingSignature(sig *types.Signature) bool {
+	params := sig.Params()
+	if params.Len() == 0 || params.Len() > 2 {
+		return false
+	}
+
+	if !isString(params.At(0).Type()) {
+		return false
+	}
+
+	return params.Len() == 1 || sig.Variadic()
+}
+
+func isString(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// ExtractStringLit returns the unquoted text of a string literal, or of the
+// literal pieces a concatenation starts with. Use LogCall.Text when type
+// information is available.
 func ExtractStringLit(expr ast.Expr) (string, bool) {
-	switch v := expr.(type) {
+	switch v := ast.Unparen(expr).(type) {
 	case *ast.BasicLit:
 		if v.Kind == token.STRING {
-			str := v.Value
-			if len(str) >= 2 {
-				return str[1 : len(str)-1], true
+			str, err := strconv.Unquote(v.Value)
+			if err != nil {
+				return "", false
 			}
+			return str, true
 		}
 
 	case *ast.BinaryExpr:
-		if left, ok := ExtractStringLit(v.X); ok {
-			return left, true
+		if v.Op != token.ADD {
+			return "", false
+		}
+		left, ok := ExtractStringLit(v.X)
+		if !ok {
+			return "", false
+		}
+		if right, ok := ExtractStringLit(v.Y); ok {
+			return left + right, true
 		}
+		return left, true
 	}
 
 	return "", false
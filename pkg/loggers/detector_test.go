@@ -0,0 +1,688 @@
+package loggers
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/internal/testutil"
+)
+
+func TestDetectorLoggerTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []LoggerType
+	}{
+		{
+			name: "package functions",
+			body: `log.Print("a"); slog.Info("b")`,
+			want: []LoggerType{LogLogger, SlogLogger},
+		},
+		{
+			name: "pointer receivers",
+			body: `l := log.Default(); l.Println("a"); s := slog.Default(); s.Warn("b")`,
+			want: []LoggerType{LogLogger, SlogLogger},
+		},
+		{
+			name: "zap loggers",
+			body: `zap.L().Info("a"); zap.L().Sugar().Infow("b")`,
+			want: []LoggerType{ZapLogger, ZapSugarLogger},
+		},
+		{
+			name: "embedded logger",
+			body: `s := service{slog.Default()}; s.Error("a")`,
+			want: []LoggerType{SlogLogger},
+		},
+		{
+			name: "alias",
+			body: `var l aliasLogger = slog.Default(); l.Info("a")`,
+			want: []LoggerType{SlogLogger},
+		},
+		{
+			name: "generic instantiation",
+			body: `w := wrapper[int]{slog.Default()}; w.Info("a")`,
+			want: []LoggerType{SlogLogger},
+		},
+		{
+			name: "logging interface",
+			body: `var l infoLogger = slog.Default(); l.Info("a")`,
+			want: []LoggerType{InterfaceLogger},
+		},
+		{
+			name: "lookalike types",
+			body: `c := catalog{}; c.Error("a"); v := slog.StringValue("b"); _ = v.String(); var tb testing.TB; tb.Errorf("failed for %s", "a")`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := `package test
+
+import (
+	"log"
+	"log/slog"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type service struct{ *slog.Logger }
+
+type aliasLogger = *slog.Logger
+
+type wrapper[T any] struct{ *slog.Logger }
+
+type infoLogger interface {
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+type catalog struct{}
+
+func (catalog) Error(msg string) {}
+
+var _ = zap.L
+var _ = log.Print
+var _ testing.TB
+
+func f() {
+	` + tt.body + `
+}
+`
+			pass, file := testutil.NewPass(t, src)
+
+			var got []LoggerType
+			for _, logCall := range NewDetector(pass).DetectLogCalls(file) {
+				got = append(got, logCall.Logger)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("DetectLogCalls() loggers = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("DetectLogCalls() loggers = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectorMethodSpecs(t *testing.T) {
+	src := `package test
+
+import (
+	"context"
+	"log"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+func f(ctx context.Context) {
+	slog.InfoContext(ctx, "info context")
+	slog.Log(ctx, slog.LevelWarn, "log")
+	slog.Default().LogAttrs(ctx, slog.LevelError, "log attrs")
+	log.Output(2, "output")
+	zap.L().DPanic("dpanic")
+	zap.L().Sync()
+	zap.L().Sugar().Fatalw("fatalw", "key", 1)
+	zap.L().Sugar().Infoln("infoln")
+	slog.Default().With("key", 1)
+}
+`
+	want := []struct {
+		message string
+		level   Level
+	}{
+		{`"info context"`, LevelInfo},
+		{`"log"`, LevelUnknown},
+		{`"log attrs"`, LevelUnknown},
+		{`"output"`, LevelInfo},
+		{`"dpanic"`, LevelPanic},
+		{`"fatalw"`, LevelFatal},
+		{`"infoln"`, LevelInfo},
+	}
+
+	pass, file := testutil.NewPass(t, src)
+	logCalls := NewDetector(pass).DetectLogCalls(file)
+
+	if len(logCalls) != len(want) {
+		t.Fatalf("DetectLogCalls() found %d calls, want %d", len(logCalls), len(want))
+	}
+
+	for i, logCall := range logCalls {
+		lit, ok := logCall.Message.(*ast.BasicLit)
+		if !ok || lit.Value != want[i].message {
+			t.Errorf("call %d: message = %v, want %s", i, logCall.Message, want[i].message)
+		}
+		if logCall.Spec.Level != want[i].level {
+			t.Errorf("call %d: level = %s, want %s", i, logCall.Spec.Level, want[i].level)
+		}
+	}
+}
+
+func TestDetectorPrintfWrappers(t *testing.T) {
+	src := `package test
+
+import (
+	"context"
+	"log"
+)
+
+func Debugf(format string, args ...any) { log.Printf(format, args...) }
+
+func Warnf(format string, args ...any) { log.Printf(format, args...) }
+
+func Bad(format string, n int) {}
+
+type L struct{}
+
+func (l *L) Logf(ctx context.Context, format string, args ...any) {}
+
+func f(ctx context.Context, l *L) {
+	Debugf("debugf %d", 1)
+	Warnf("warnf")
+	Bad("bad", 1)
+	l.Logf(ctx, "logf %s", "a")
+	(*L).Logf(l, ctx, "method expression")
+}
+`
+	want := []struct {
+		message string
+		logger  LoggerType
+		level   Level
+	}{
+		{"format", LogLogger, LevelInfo},
+		{"format", LogLogger, LevelInfo},
+		{`"debugf %d"`, WrapperLogger, LevelDebug},
+		{`"logf %s"`, WrapperLogger, LevelUnknown},
+		{`"method expression"`, WrapperLogger, LevelUnknown},
+	}
+
+	pass, file := testutil.NewPass(t, src)
+	detector := NewDetector(pass)
+	detector.SetPrintfWrappers([]string{"test.Debugf", "test.Bad", "(*test.L).Logf"})
+	logCalls := detector.DetectLogCalls(file)
+
+	if len(logCalls) != len(want) {
+		t.Fatalf("DetectLogCalls() found %d calls, want %d", len(logCalls), len(want))
+	}
+
+	for i, logCall := range logCalls {
+		if got := types.ExprString(logCall.Message); got != want[i].message {
+			t.Errorf("call %d: message = %s, want %s", i, got, want[i].message)
+		}
+		if logCall.Logger != want[i].logger {
+			t.Errorf("call %d: logger = %s, want %s", i, logCall.Logger, want[i].logger)
+		}
+		if logCall.Level != want[i].level {
+			t.Errorf("call %d: level = %s, want %s", i, logCall.Level, want[i].level)
+		}
+		if !logCall.Format {
+			t.Errorf("call %d: not detected as printf-style", i)
+		}
+	}
+}
+
+func TestDetectorZerolog(t *testing.T) {
+	src := `package test
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func f(logger zerolog.Logger, id string) {
+	log.Info().Str("user", id).Int("attempt", 2).Msg("login")
+	logger.Warn().Err(errors.New("x")).Timestamp().Msgf("retry %d", 1)
+	log.WithLevel(zerolog.ErrorLevel).Dict("req", zerolog.Dict().Str("id", id)).Msg("failed")
+	e := logger.Debug()
+	e.Msg("held event")
+	log.Info().Send()
+	logger.Printf("print %s", id)
+}
+`
+	want := []struct {
+		message string
+		level   Level
+		format  bool
+		keys    []string
+	}{
+		{`"login"`, LevelInfo, false, []string{"user", "attempt"}},
+		{`"retry %d"`, LevelWarn, true, []string{"error"}},
+		{`"failed"`, LevelError, false, []string{"req"}},
+		{`"held event"`, LevelUnknown, false, nil},
+		{`"print %s"`, LevelDebug, true, nil},
+	}
+
+	pass, file := testutil.NewPass(t, src)
+	logCalls := NewDetector(pass).DetectLogCalls(file)
+
+	if len(logCalls) != len(want) {
+		t.Fatalf("DetectLogCalls() found %d calls, want %d", len(logCalls), len(want))
+	}
+
+	for i, logCall := range logCalls {
+		if got := types.ExprString(logCall.Message); got != want[i].message {
+			t.Errorf("call %d: message = %s, want %s", i, got, want[i].message)
+		}
+		if logCall.Logger != ZerologLogger {
+			t.Errorf("call %d: logger = %s, want %s", i, logCall.Logger, ZerologLogger)
+		}
+		if logCall.Level != want[i].level {
+			t.Errorf("call %d: level = %s, want %s", i, logCall.Level, want[i].level)
+		}
+		if logCall.Format != want[i].format {
+			t.Errorf("call %d: format = %v, want %v", i, logCall.Format, want[i].format)
+		}
+
+		var keys []string
+		for _, attr := range logCall.Attrs {
+			keys = append(keys, attr.Key)
+		}
+		if !slices.Equal(keys, want[i].keys) {
+			t.Errorf("call %d: attribute keys = %q, want %q", i, keys, want[i].keys)
+		}
+	}
+
+	if group := logCalls[2].Attrs[0].Group; len(group) != 1 || group[0].Key != "id" {
+		t.Errorf("Dict group = %v, want the id field", group)
+	}
+}
+
+func TestDetectorLogrus(t *testing.T) {
+	src := `package test
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+func f(logger *logrus.Logger, id string) {
+	logrus.WithField("user", id).WithFields(logrus.Fields{"attempt": 2, "ok": true}).Info("login")
+	logger.WithError(errors.New("x")).Warnf("retry %d", 1)
+	entry := logger.WithField("request", id)
+	entry.Log(logrus.ErrorLevel, "failed")
+	logrus.Warning("warning")
+}
+`
+	want := []struct {
+		message string
+		level   Level
+		format  bool
+		keys    []string
+	}{
+		{`"login"`, LevelInfo, false, []string{"user", "attempt", "ok"}},
+		{`"retry %d"`, LevelWarn, true, []string{"error"}},
+		{`"failed"`, LevelError, false, []string{"request"}},
+		{`"warning"`, LevelWarn, false, nil},
+	}
+
+	pass, file := testutil.NewPass(t, src)
+	logCalls := NewDetector(pass).DetectLogCalls(file)
+
+	if len(logCalls) != len(want) {
+		t.Fatalf("DetectLogCalls() found %d calls, want %d", len(logCalls), len(want))
+	}
+
+	for i, logCall := range logCalls {
+		if got := types.ExprString(logCall.Message); got != want[i].message {
+			t.Errorf("call %d: message = %s, want %s", i, got, want[i].message)
+		}
+		if logCall.Logger != LogrusLogger {
+			t.Errorf("call %d: logger = %s, want %s", i, logCall.Logger, LogrusLogger)
+		}
+		if logCall.Level != want[i].level {
+			t.Errorf("call %d: level = %s, want %s", i, logCall.Level, want[i].level)
+		}
+		if logCall.Format != want[i].format {
+			t.Errorf("call %d: format = %v, want %v", i, logCall.Format, want[i].format)
+		}
+
+		var keys []string
+		for _, attr := range logCall.Attrs {
+			if !attr.FromWith {
+				t.Errorf("call %d: attribute %q not marked as added by With", i, attr.Key)
+			}
+			keys = append(keys, attr.Key)
+		}
+		if !slices.Equal(keys, want[i].keys) {
+			t.Errorf("call %d: attribute keys = %q, want %q", i, keys, want[i].keys)
+		}
+	}
+}
+
+func TestDetectorInterfaceLevels(t *testing.T) {
+	src := `package test
+
+type logger interface {
+	Info(msg string, args ...any)
+	Fatal(msg string, args ...any)
+	Panic(msg string, args ...any)
+}
+
+func f(l logger) {
+	l.Info("info", "user", 1)
+	l.Fatal("fatal", "user", 1)
+	l.Panic("panic", "user", 1)
+}
+`
+	want := []struct {
+		level Level
+		keys  []string
+	}{
+		{LevelInfo, []string{"user"}},
+		{LevelFatal, []string{"user"}},
+		{LevelPanic, []string{"user"}},
+	}
+
+	pass, file := testutil.NewPass(t, src)
+	logCalls := NewDetector(pass).DetectLogCalls(file)
+
+	if len(logCalls) != len(want) {
+		t.Fatalf("DetectLogCalls() found %d calls, want %d", len(logCalls), len(want))
+	}
+
+	for i, logCall := range logCalls {
+		if logCall.Level != want[i].level {
+			t.Errorf("call %d: level = %s, want %s", i, logCall.Level, want[i].level)
+		}
+
+		var keys []string
+		for _, attr := range logCall.Attrs {
+			keys = append(keys, attr.Key)
+		}
+		if !slices.Equal(keys, want[i].keys) {
+			t.Errorf("call %d: attribute keys = %q, want %q", i, keys, want[i].keys)
+		}
+	}
+}
+
+func TestDetectorFuncValues(t *testing.T) {
+	src := `package test
+
+import (
+	"log"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+func f(cond bool, injected func(string, ...any)) {
+	logf := log.Printf
+	logf("printf %d", 1)
+
+	defer slog.Error("deferred")
+
+	logger := zap.L()
+	h := logger.Info
+	h("method value")
+
+	errorFn := map[bool]func(string, ...any){true: slog.Error, false: slog.Info}
+	errorFn[cond]("indexed")
+
+	info := (*slog.Logger).Info
+	info(slog.Default(), "method expression")
+
+	alias := logf
+	alias("alias")
+
+	reassigned := slog.Info
+	reassigned = func(string, ...any) {}
+	reassigned("reassigned")
+
+	injected("injected")
+}
+
+type server struct {
+	logf   func(string, ...any)
+	warn   func(string, ...any)
+	levels map[bool]func(string, ...any)
+	hook   func(string, ...any)
+	notify func(string, ...any)
+}
+
+func newServer(logf func(string, ...any)) *server {
+	return &server{logf: logf, levels: map[bool]func(string, ...any){true: slog.Error, false: slog.Info}}
+}
+
+func newNotifier(notify func(string, ...any)) *server {
+	return &server{notify: notify}
+}
+
+func (s *server) serve(cond bool) {
+	s.logf("constructor")
+	s.warn("assigned")
+	s.levels[cond]("indexed field")
+	s.hook("reassigned field")
+	s.notify("different arguments")
+}
+
+func g() {
+	s := newServer(log.Printf)
+	s.warn = slog.Warn
+	s.hook = slog.Info
+	s.hook = func(string, ...any) {}
+	newNotifier(slog.Info)
+	newNotifier(slog.Warn)
+}
+`
+	want := []struct {
+		message string
+		logger  LoggerType
+		level   Level
+	}{
+		{`"printf %d"`, LogLogger, LevelInfo},
+		{`"deferred"`, SlogLogger, LevelError},
+		{`"method value"`, ZapLogger, LevelInfo},
+		{`"indexed"`, SlogLogger, LevelUnknown},
+		{`"method expression"`, SlogLogger, LevelInfo},
+		{`"alias"`, LogLogger, LevelInfo},
+		{`"constructor"`, LogLogger, LevelInfo},
+		{`"assigned"`, SlogLogger, LevelWarn},
+		{`"indexed field"`, SlogLogger, LevelUnknown},
+	}
+
+	pass, file := testutil.NewPass(t, src)
+	logCalls := NewDetector(pass).DetectLogCalls(file)
+
+	if len(logCalls) != len(want) {
+		t.Fatalf("DetectLogCalls() found %d calls, want %d", len(logCalls), len(want))
+	}
+
+	for i, logCall := range logCalls {
+		lit, ok := logCall.Message.(*ast.BasicLit)
+		if !ok || lit.Value != want[i].message {
+			t.Errorf("call %d: message = %v, want %s", i, logCall.Message, want[i].message)
+		}
+		if logCall.Logger != want[i].logger {
+			t.Errorf("call %d: logger = %s, want %s", i, logCall.Logger, want[i].logger)
+		}
+		if logCall.Spec.Level != want[i].level {
+			t.Errorf("call %d: level = %s, want %s", i, logCall.Spec.Level, want[i].level)
+		}
+	}
+}
+
+func TestDetectorCallModel(t *testing.T) {
+	src := `package test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+func f(ctx context.Context, id string) {
+	slog.With("service", "api").InfoContext(ctx, "request", "id", id, slog.Group("http", "method", "GET"), 42)
+	slog.Log(ctx, slog.LevelWarn, "log", slog.Int("count", 1))
+	zap.L().With(zap.String("service", "api")).Error("failed", zap.Error(errors.New("x")), zap.Dict("db", zap.Int("port", 5432)))
+	zap.L().Sugar().With("service", "api").Infof("user %s", id)
+}
+`
+	type attr struct {
+		key      string
+		group    []string
+		fromWith bool
+	}
+	want := []struct {
+		level      Level
+		context    bool
+		formatArgs int
+		attrs      []attr
+	}{
+		{
+			level:   LevelInfo,
+			context: true,
+			attrs:   []attr{{key: "service", fromWith: true}, {key: "id"}, {key: "http", group: []string{"method"}}, {}},
+		},
+		{
+			level:   LevelWarn,
+			context: true,
+			attrs:   []attr{{key: "count"}},
+		},
+		{
+			level: LevelError,
+			attrs: []attr{{key: "service", fromWith: true}, {key: "error"}, {key: "db", group: []string{"port"}}},
+		},
+		{
+			level:      LevelInfo,
+			formatArgs: 1,
+			attrs:      []attr{{key: "service", fromWith: true}},
+		},
+	}
+
+	pass, file := testutil.NewPass(t, src)
+	logCalls := NewDetector(pass).DetectLogCalls(file)
+
+	if len(logCalls) != len(want) {
+		t.Fatalf("DetectLogCalls() found %d calls, want %d", len(logCalls), len(want))
+	}
+
+	for i, logCall := range logCalls {
+		if logCall.Level != want[i].level {
+			t.Errorf("call %d: level = %s, want %s", i, logCall.Level, want[i].level)
+		}
+		if (logCall.Context != nil) != want[i].context {
+			t.Errorf("call %d: context = %v, want %v", i, logCall.Context, want[i].context)
+		}
+		if len(logCall.FormatArgs) != want[i].formatArgs {
+			t.Errorf("call %d: %d format args, want %d", i, len(logCall.FormatArgs), want[i].formatArgs)
+		}
+		if len(logCall.Attrs) != len(want[i].attrs) {
+			t.Fatalf("call %d: %d attrs, want %d", i, len(logCall.Attrs), len(want[i].attrs))
+		}
+		for j, got := range logCall.Attrs {
+			wantAttr := want[i].attrs[j]
+			if got.Key != wantAttr.key || got.FromWith != wantAttr.fromWith {
+				t.Errorf("call %d attr %d: key = %q (with %v), want %q (with %v)",
+					i, j, got.Key, got.FromWith, wantAttr.key, wantAttr.fromWith)
+			}
+			if len(got.Group) != len(wantAttr.group) {
+				t.Errorf("call %d attr %d: %d nested attrs, want %d", i, j, len(got.Group), len(wantAttr.group))
+				continue
+			}
+			for k, nested := range got.Group {
+				if nested.Key != wantAttr.group[k] {
+					t.Errorf("call %d attr %d: nested key = %q, want %q", i, j, nested.Key, wantAttr.group[k])
+				}
+			}
+		}
+	}
+}
+
+func TestDetectorWithCalls(t *testing.T) {
+	src := `package test
+
+import (
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+type Logger struct{}
+
+func (l *Logger) Info(msg string, args ...any) {}
+
+func f(l *Logger, param *slog.Logger) {
+	base := slog.With("service", "api")
+	reqLogger := base.With("request", 1)
+	reqLogger.Info("handled", "status", 200)
+
+	changed := slog.With("a", 1)
+	changed = slog.With("b", 2)
+	changed.Info("changed")
+
+	param.With("param", true).Info("param")
+	zap.L().With(zap.Int("port", 80))
+	l.Info("wrapped", "user", 1)
+}
+`
+	pass, file := testutil.NewPass(t, src)
+	detector := NewDetector(pass)
+	detector.SetSlogWrappers([]string{"(*test.Logger).Info"})
+
+	keys := func(attrs []Attr) []string {
+		var keys []string
+		for _, attr := range attrs {
+			key := attr.Key
+			if attr.FromWith {
+				key += " (with)"
+			}
+			keys = append(keys, key)
+		}
+		return keys
+	}
+
+	wantLogCalls := [][]string{
+		{"service (with)", "request (with)", "status"},
+		nil,
+		{"param (with)"},
+		{"user"},
+	}
+	logCalls := detector.DetectLogCalls(file)
+	if len(logCalls) != len(wantLogCalls) {
+		t.Fatalf("DetectLogCalls() found %d calls, want %d", len(logCalls), len(wantLogCalls))
+	}
+	for i, logCall := range logCalls {
+		if got := keys(logCall.Attrs); !slices.Equal(got, wantLogCalls[i]) {
+			t.Errorf("call %d: attrs = %q, want %q", i, got, wantLogCalls[i])
+		}
+	}
+	if wrapped := logCalls[3]; wrapped.Logger != WrapperLogger || wrapped.Level != LevelInfo || wrapped.Format {
+		t.Errorf("wrapper call: logger %s, level %s, format %v", wrapped.Logger, wrapped.Level, wrapped.Format)
+	}
+
+	wantWithCalls := []struct {
+		logger LoggerType
+		keys   []string
+	}{
+		{SlogLogger, []string{"service"}},
+		{SlogLogger, []string{"service (with)", "request"}},
+		{SlogLogger, []string{"a"}},
+		{SlogLogger, []string{"b"}},
+		{SlogLogger, []string{"param"}},
+		{ZapLogger, []string{"port"}},
+	}
+	withCalls := detector.DetectWithCalls(file)
+	if len(withCalls) != len(wantWithCalls) {
+		t.Fatalf("DetectWithCalls() found %d calls, want %d", len(withCalls), len(wantWithCalls))
+	}
+	for i, withCall := range withCalls {
+		if withCall.Logger != wantWithCalls[i].logger {
+			t.Errorf("with call %d: logger = %s, want %s", i, withCall.Logger, wantWithCalls[i].logger)
+		}
+		if got := keys(withCall.Attrs); !slices.Equal(got, wantWithCalls[i].keys) {
+			t.Errorf("with call %d: attrs = %q, want %q", i, got, wantWithCalls[i].keys)
+		}
+	}
+}
@@ -0,0 +1,455 @@
+package loggers
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// logFunc is a logging function or method, possibly used as a value.
+type logFunc struct {
+	logger LoggerType
+	method string
+	spec   MethodSpec
+	// held as an element of a map or slice and called through an index
+	indexed bool
+}
+
+func (d *Detector) newLogFunc(fn *types.Func, methodExpr bool) *logFunc {
+	loggerType := LoggerTypeOf(fn)
+	spec, ok := LookupMethod(loggerType, fn.Name())
+	if !ok {
+		loggerType = WrapperLogger
+		spec, ok = d.wrapperSpec(fn)
+	}
+	if !ok {
+		return nil
+	}
+
+	// method expressions like (*slog.Logger).Info take the receiver first
+	if methodExpr {
+		spec.MessageIndex++
+		if spec.AttrsIndex >= 0 {
+			spec.AttrsIndex++
+		}
+	}
+
+	return &logFunc{
+		logger: loggerType,
+		method: fn.Name(),
+		spec:   spec,
+	}
+}
+
+// funcValues maps local variables to the logging function they hold. A nil
+// entry marks a variable that is also assigned something else, so calls
+// through it can't be resolved.
+type funcValues map[*types.Var]*logFunc
+
+func (v funcValues) lookup(obj *types.Var, indexed bool) *logFunc {
+	target := v[obj]
+	if target == nil || target.indexed != indexed {
+		return nil
+	}
+
+	return target
+}
+
+func (v funcValues) bind(obj *types.Var, target *logFunc) {
+	prev, seen := v[obj]
+	if !seen {
+		v[obj] = target
+		return
+	}
+
+	if prev == nil || target == nil || *prev != *target {
+		v[obj] = nil
+	}
+}
+
+// collectFuncValues records function-typed local variables of a file that
+// are assigned logging functions, like logf := log.Printf or
+// fns := map[bool]func(string, ...any){true: slog.Error, false: slog.Info}.
+func (d *Detector) collectFuncValues(file *ast.File) funcValues {
+	values := make(funcValues)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			if len(stmt.Lhs) != len(stmt.Rhs) {
+				for _, lhs := range stmt.Lhs {
+					d.bindValue(values, lhs, nil)
+				}
+				return true
+			}
+			for i, lhs := range stmt.Lhs {
+				d.bindValue(values, lhs, d.resolveValue(values, stmt.Rhs[i]))
+			}
+
+		case *ast.ValueSpec:
+			if len(stmt.Names) != len(stmt.Values) {
+				return true
+			}
+			for i, name := range stmt.Names {
+				d.bindValue(values, name, d.resolveValue(values, stmt.Values[i]))
+			}
+
+		case *ast.RangeStmt:
+			d.bindValue(values, stmt.Key, nil)
+			d.bindValue(values, stmt.Value, nil)
+
+		case *ast.FuncType:
+			// parameters hold whatever the caller passes
+			for _, field := range stmt.Params.List {
+				for _, name := range field.Names {
+					d.bindValue(values, name, nil)
+				}
+			}
+
+		case *ast.UnaryExpr:
+			// the variable may be changed through its address
+			if stmt.Op == token.AND {
+				d.bindValue(values, stmt.X, nil)
+			}
+		}
+
+		return true
+	})
+
+	return values
+}
+
+func (d *Detector) bindValue(values funcValues, lhs ast.Expr, target *logFunc) {
+	if lhs == nil {
+		return
+	}
+
+	lhs = ast.Unparen(lhs)
+	if index, ok := lhs.(*ast.IndexExpr); ok {
+		// storing into a map or slice invalidates what we know about it
+		lhs, target = ast.Unparen(index.X), nil
+	}
+
+	ident, ok := lhs.(*ast.Ident)
+	if !ok {
+		return
+	}
+
+	obj, ok := d.pass.TypesInfo.ObjectOf(ident).(*types.Var)
+	if !ok || !isLocal(obj) {
+		return
+	}
+
+	values.bind(obj, target)
+}
+
+// resolveValue resolves the logging function an expression evaluates to.
+func (d *Detector) resolveValue(values funcValues, expr ast.Expr) *logFunc {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		if selection, ok := d.pass.TypesInfo.Selections[e]; ok {
+			fn, ok := selection.Obj().(*types.Func)
+			if !ok || selection.Kind() == types.FieldVal {
+				return nil
+			}
+			return d.newLogFunc(fn, selection.Kind() == types.MethodExpr)
+		}
+		if fn, ok := d.pass.TypesInfo.Uses[e.Sel].(*types.Func); ok {
+			return d.newLogFunc(fn, false)
+		}
+
+	case *ast.Ident:
+		switch obj := d.pass.TypesInfo.Uses[e].(type) {
+		case *types.Func:
+			return d.newLogFunc(obj, false)
+		case *types.Var:
+			return values[obj]
+		}
+
+	case *ast.CompositeLit:
+		return d.resolveElements(values, e)
+	}
+
+	return nil
+}
+
+// resolveElements resolves a map or slice literal whose elements are all
+// logging functions taking their arguments the same way. The level is kept
+// only if every element logs at the same level.
+func (d *Detector) resolveElements(values funcValues, lit *ast.CompositeLit) *logFunc {
+	var target *logFunc
+
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+
+		elem := d.resolveValue(values, elt)
+		if elem == nil || elem.indexed {
+			return nil
+		}
+
+		if target == nil {
+			target = &logFunc{logger: elem.logger, method: elem.method, spec: elem.spec, indexed: true}
+			continue
+		}
+
+		if elem.logger != target.logger || elem.spec.MessageIndex != target.spec.MessageIndex ||
+			elem.spec.Printf != target.spec.Printf || elem.spec.AttrsIndex != target.spec.AttrsIndex {
+			return nil
+		}
+
+		if elem.spec.Level != target.spec.Level {
+			target.spec.Level = LevelUnknown
+		}
+	}
+
+	return target
+}
+
+// collectFieldValues records function-typed fields of the package's struct
+// types that are set to logging functions, like server{logf: log.Printf} or
+// s.logf = log.Printf. A field set from a parameter of a function, like
+// &server{logf: logf} in a constructor, holds what every call of the
+// function passes.
+func (d *Detector) collectFieldValues() funcValues {
+	locals := make([]funcValues, len(d.pass.Files))
+	for i, file := range d.pass.Files {
+		locals[i] = d.collectFuncValues(file)
+	}
+
+	params := d.collectParamValues(locals)
+	resolve := func(values funcValues, expr ast.Expr) *logFunc {
+		if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+			if obj, ok := d.pass.TypesInfo.Uses[ident].(*types.Var); ok && params[obj] != nil {
+				return params[obj]
+			}
+		}
+		return d.resolveValue(values, expr)
+	}
+
+	fields := make(funcValues)
+
+	for i, file := range d.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch expr := n.(type) {
+			case *ast.CompositeLit:
+				st, ok := d.pass.TypesInfo.TypeOf(expr).Underlying().(*types.Struct)
+				if !ok {
+					return true
+				}
+				for j, elt := range expr.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						if key, ok := kv.Key.(*ast.Ident); ok {
+							d.bindField(fields, key, resolve(locals[i], kv.Value))
+						}
+						continue
+					}
+					if j < st.NumFields() {
+						bindField(fields, st.Field(j), resolve(locals[i], elt))
+					}
+				}
+
+			case *ast.AssignStmt:
+				for j, lhs := range expr.Lhs {
+					var target *logFunc
+					if len(expr.Lhs) == len(expr.Rhs) {
+						target = resolve(locals[i], expr.Rhs[j])
+					}
+					d.bindField(fields, lhs, target)
+				}
+
+			case *ast.UnaryExpr:
+				// the field may be changed through its address
+				if expr.Op == token.AND {
+					d.bindField(fields, expr.X, nil)
+				}
+			}
+
+			return true
+		})
+	}
+
+	return fields
+}
+
+// bindField binds the field an expression selects, like s.logf or the key of
+// a struct literal.
+func (d *Detector) bindField(fields funcValues, expr ast.Expr, target *logFunc) {
+	expr = ast.Unparen(expr)
+	if index, ok := expr.(*ast.IndexExpr); ok {
+		expr, target = ast.Unparen(index.X), nil
+	}
+
+	switch e := expr.(type) {
+	case *ast.SelectorExpr:
+		if selection, ok := d.pass.TypesInfo.Selections[e]; ok && selection.Kind() == types.FieldVal {
+			bindField(fields, selection.Obj().(*types.Var), target)
+		}
+	case *ast.Ident:
+		if obj, ok := d.pass.TypesInfo.Uses[e].(*types.Var); ok && obj.IsField() {
+			bindField(fields, obj, target)
+		}
+	}
+}
+
+func bindField(fields funcValues, field *types.Var, target *logFunc) {
+	// other packages may set exported fields
+	if field.Exported() || field.Pkg() == nil {
+		return
+	}
+
+	fields.bind(field, target)
+}
+
+// collectParamValues records function-typed parameters of the package's
+// unexported functions that every call passes the same logging function.
+// Functions used as values may be called with anything, so their parameters
+// aren't recorded.
+func (d *Detector) collectParamValues(locals []funcValues) funcValues {
+	params := make(funcValues)
+	called := make(map[*ast.Ident]bool)
+	var escaped []*types.Func
+
+	for i, file := range d.pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch expr := n.(type) {
+			case *ast.CallExpr:
+				ident, ok := ast.Unparen(expr.Fun).(*ast.Ident)
+				if !ok {
+					return true
+				}
+				fn, ok := d.pass.TypesInfo.Uses[ident].(*types.Func)
+				if !ok || fn.Pkg() != d.pass.Pkg || fn.Exported() {
+					return true
+				}
+				called[ident] = true
+
+				sig := fn.Type().(*types.Signature)
+				for j := range sig.Params().Len() {
+					var target *logFunc
+					if len(expr.Args) == sig.Params().Len() && !expr.Ellipsis.IsValid() && !(sig.Variadic() && j == sig.Params().Len()-1) {
+						target = d.resolveValue(locals[i], expr.Args[j])
+					}
+					params.bind(sig.Params().At(j), target)
+				}
+
+			case *ast.Ident:
+				if fn, ok := d.pass.TypesInfo.Uses[expr].(*types.Func); ok && !called[expr] {
+					escaped = append(escaped, fn)
+				}
+
+			case *ast.AssignStmt:
+				for _, lhs := range expr.Lhs {
+					d.invalidateParam(params, lhs)
+				}
+
+			case *ast.UnaryExpr:
+				if expr.Op == token.AND {
+					d.invalidateParam(params, expr.X)
+				}
+			}
+
+			return true
+		})
+	}
+
+	for _, fn := range escaped {
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			continue
+		}
+		for j := range sig.Params().Len() {
+			params.bind(sig.Params().At(j), nil)
+		}
+	}
+
+	return params
+}
+
+// invalidateParam marks a parameter assigned in the function body.
+func (d *Detector) invalidateParam(params funcValues, expr ast.Expr) {
+	if ident, ok := ast.Unparen(expr).(*ast.Ident); ok {
+		if obj, ok := d.pass.TypesInfo.ObjectOf(ident).(*types.Var); ok && isLocal(obj) {
+			params.bind(obj, nil)
+		}
+	}
+}
+
+func isLocal(obj *types.Var) bool {
+	if obj.IsField() || obj.Pkg() == nil || obj.Parent() == nil {
+		return false
+	}
+
+	return obj.Parent() != obj.Pkg().Scope()
+}
+
+// loggerValues maps local variables to the expression they are assigned,
+// like l := slog.With("service", "api"), so that the attributes of With
+// calls can be followed through them. A nil entry marks a variable that is
+// assigned more than once.
+type loggerValues map[*types.Var]ast.Expr
+
+func (v loggerValues) bind(obj *types.Var, expr ast.Expr) {
+	if _, seen := v[obj]; seen {
+		v[obj] = nil
+		return
+	}
+
+	v[obj] = expr
+}
+
+// collectLoggerValues records the local variables of a file assigned once.
+func (d *Detector) collectLoggerValues(file *ast.File) loggerValues {
+	values := make(loggerValues)
+
+	bind := func(lhs, rhs ast.Expr) {
+		ident, ok := ast.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return
+		}
+		if obj, ok := d.pass.TypesInfo.ObjectOf(ident).(*types.Var); ok && isLocal(obj) {
+			values.bind(obj, rhs)
+		}
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch stmt := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range stmt.Lhs {
+				var rhs ast.Expr
+				if len(stmt.Lhs) == len(stmt.Rhs) && (stmt.Tok == token.ASSIGN || stmt.Tok == token.DEFINE) {
+					rhs = stmt.Rhs[i]
+				}
+				bind(lhs, rhs)
+			}
+
+		case *ast.ValueSpec:
+			for i, name := range stmt.Names {
+				if len(stmt.Names) == len(stmt.Values) {
+					bind(name, stmt.Values[i])
+				}
+			}
+
+		case *ast.RangeStmt:
+			bind(stmt.Key, nil)
+			bind(stmt.Value, nil)
+
+		case *ast.FuncType:
+			for _, field := range stmt.Params.List {
+				for _, name := range field.Names {
+					bind(name, nil)
+				}
+			}
+
+		case *ast.UnaryExpr:
+			if stmt.Op == token.AND {
+				bind(stmt.X, nil)
+			}
+		}
+
+		return true
+	})
+
+	return values
+}
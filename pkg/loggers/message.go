@@ -0,0 +1,320 @@
+package loggers
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type SegmentKind int
+
+const (
+	// constant text
+	LiteralSegment SegmentKind = iota
+	// printf verb like %d, printed from its argument
+	VerbSegment
+	// value only known at run time
+	DynamicSegment
+)
+
+// Rune is a character of a message with the source span it was written as.
+// Escape sequences span their whole escape, characters of named constants
+// span the constant's name.
+type Rune struct {
+	Value rune
+	Pos   token.Pos
+	End   token.Pos
+}
+
+// Segment is a piece of a log message.
+type Segment struct {
+	Kind SegmentKind
+	// text of a literal segment, or the verb as written for a verb segment
+	Text  string
+	Runes []Rune
+	// Runes map to the characters of a string literal in the log call, so
+	// they can be replaced by a fix. False for text of named constants.
+	Exact bool
+	// expression of a dynamic segment, or the argument of a verb (nil if the
+	// verb has no matching argument)
+	Expr ast.Expr
+}
+
+// Message is the text of a log message split into literal text, format
+// verbs and dynamic values.
+type Message struct {
+	Segments []Segment
+}
+
+// Literal returns the constant text of the message. Verbs and dynamic parts
+// are left out.
+func (m Message) Literal() string {
+	var b strings.Builder
+
+	for _, seg := range m.Segments {
+		if seg.Kind == LiteralSegment {
+			b.WriteString(seg.Text)
+		}
+	}
+
+	return b.String()
+}
+
+// IsConstant reports whether the whole message is known at compile time.
+func (m Message) IsConstant() bool {
+	for _, seg := range m.Segments {
+		if seg.Kind != LiteralSegment {
+			return false
+		}
+	}
+
+	return true
+}
+
+// buildMessage resolves the pieces of a message expression: string literals,
+// named constants, concatenations and fmt.Sprintf calls.
+func (d *Detector) buildMessage(expr ast.Expr) Message {
+	return Message{Segments: d.segments(expr)}
+}
+
+func (d *Detector) segments(expr ast.Expr) []Segment {
+	expr = ast.Unparen(expr)
+
+	if tv, ok := d.pass.TypesInfo.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		switch e := expr.(type) {
+		case *ast.BasicLit:
+			if seg, ok := literalSegment(e); ok {
+				return []Segment{seg}
+			}
+		case *ast.BinaryExpr:
+			return append(d.segments(e.X), d.segments(e.Y)...)
+		}
+
+		return []Segment{constSegment(expr, constant.StringVal(tv.Value))}
+	}
+
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return append(d.segments(e.X), d.segments(e.Y)...)
+		}
+	case *ast.CallExpr:
+		if fn := d.funcOf(e.Fun); fn != nil && fn.Pkg() != nil &&
+			fn.Pkg().Path() == "fmt" && fn.Name() == "Sprintf" && len(e.Args) > 0 && !e.Ellipsis.IsValid() {
+			return applyFormat(d.segments(e.Args[0]), e.Args[1:])
+		}
+	}
+
+	return []Segment{{Kind: DynamicSegment, Expr: expr}}
+}
+
+// constSegment is the text of a constant that isn't spelled out in place.
+// Every character spans the whole expression.
+func constSegment(expr ast.Expr, text string) Segment {
+	seg := Segment{Kind: LiteralSegment, Text: text}
+
+	for _, r := range text {
+		seg.Runes = append(seg.Runes, Rune{Value: r, Pos: expr.Pos(), End: expr.End()})
+	}
+
+	return seg
+}
+
+// literalSegment unquotes a string literal keeping the source span of every
+// character.
+func literalSegment(lit *ast.BasicLit) (Segment, bool) {
+	if lit.Kind != token.STRING || len(lit.Value) < 2 {
+		return Segment{}, false
+	}
+
+	var (
+		buf   []byte
+		spans []Rune
+	)
+
+	body := lit.Value[1 : len(lit.Value)-1]
+	start := lit.ValuePos + 1
+
+	if lit.Value[0] == '`' {
+		for i := 0; i < len(body); i++ {
+			// carriage returns are discarded from raw strings
+			if body[i] == '\r' {
+				continue
+			}
+			pos := start + token.Pos(i)
+			buf = append(buf, body[i])
+			spans = append(spans, Rune{Pos: pos, End: pos + 1})
+		}
+	} else {
+		for offset := 0; offset < len(body); {
+			value, multibyte, tail, err := strconv.UnquoteChar(body[offset:], lit.Value[0])
+			if err != nil {
+				return Segment{}, false
+			}
+
+			consumed := len(body) - offset - len(tail)
+			span := Rune{Pos: start + token.Pos(offset), End: start + token.Pos(offset+consumed)}
+
+			// \x and octal escapes denote single bytes
+			if !multibyte {
+				buf = append(buf, byte(value))
+				spans = append(spans, span)
+			} else {
+				encoded := utf8.AppendRune(nil, value)
+				buf = append(buf, encoded...)
+				for range encoded {
+					spans = append(spans, span)
+				}
+			}
+
+			offset += consumed
+		}
+	}
+
+	seg := Segment{Kind: LiteralSegment, Text: string(buf), Exact: true}
+
+	for i := 0; i < len(buf); {
+		value, size := utf8.DecodeRune(buf[i:])
+		seg.Runes = append(seg.Runes, Rune{Value: value, Pos: spans[i].Pos, End: spans[i+size-1].End})
+		i += size
+	}
+
+	return seg, true
+}
+
+// applyFormat splits the literal segments of a printf format into text and
+// verbs, pairing verbs with their arguments.
+func applyFormat(format []Segment, args []ast.Expr) []Segment {
+	var segments []Segment
+	argIndex := 0
+
+	nextArg := func() ast.Expr {
+		if argIndex >= len(args) {
+			argIndex++
+			return nil
+		}
+		arg := args[argIndex]
+		argIndex++
+		return arg
+	}
+
+	for _, seg := range format {
+		if seg.Kind != LiteralSegment {
+			segments = append(segments, seg)
+			continue
+		}
+
+		runes := seg.Runes
+		literalStart := 0
+
+		flush := func(end int) {
+			if end > literalStart {
+				segments = append(segments, runeSegment(LiteralSegment, runes[literalStart:end], seg.Exact))
+			}
+		}
+
+		for i := 0; i < len(runes); i++ {
+			if runes[i].Value != '%' {
+				continue
+			}
+
+			verb, explicit, stars, ok := scanVerb(runes[i:])
+			if !ok {
+				continue
+			}
+
+			flush(i)
+
+			if verb == 2 && runes[i+1].Value == '%' {
+				// %% prints a single percent sign
+				percent := runeSegment(LiteralSegment, runes[i:i+1], seg.Exact)
+				percent.Runes[0].End = runes[i+1].End
+				segments = append(segments, percent)
+			} else {
+				for range stars {
+					nextArg()
+				}
+				if explicit > 0 {
+					argIndex = explicit - 1
+				}
+				verbSeg := runeSegment(VerbSegment, runes[i:i+verb], seg.Exact)
+				verbSeg.Expr = nextArg()
+				segments = append(segments, verbSeg)
+			}
+
+			i += verb - 1
+			literalStart = i + 1
+		}
+
+		flush(len(runes))
+	}
+
+	return segments
+}
+
+func runeSegment(kind SegmentKind, runes []Rune, exact bool) Segment {
+	var text strings.Builder
+	for _, r := range runes {
+		text.WriteRune(r.Value)
+	}
+
+	return Segment{
+		Kind:  kind,
+		Text:  text.String(),
+		Runes: append([]Rune(nil), runes...),
+		Exact: exact,
+	}
+}
+
+// scanVerb scans a printf verb at the start of runes, returning its length,
+// an explicit argument index (0 if none) and the number of * arguments.
+func scanVerb(runes []Rune) (length, explicit, stars int, ok bool) {
+	i := 1
+
+	for i < len(runes) && strings.ContainsRune("+-# 0", runes[i].Value) {
+		i++
+	}
+
+	scanIndex := func() {
+		if i < len(runes) && runes[i].Value == '[' {
+			j := i + 1
+			n := 0
+			for j < len(runes) && runes[j].Value >= '0' && runes[j].Value <= '9' {
+				n = n*10 + int(runes[j].Value-'0')
+				j++
+			}
+			if j < len(runes) && runes[j].Value == ']' && n > 0 {
+				explicit = n
+				i = j + 1
+			}
+		}
+	}
+
+	scanNumber := func() {
+		scanIndex()
+		if i < len(runes) && runes[i].Value == '*' {
+			stars++
+			i++
+			return
+		}
+		for i < len(runes) && runes[i].Value >= '0' && runes[i].Value <= '9' {
+			i++
+		}
+	}
+
+	scanNumber()
+	if i < len(runes) && runes[i].Value == '.' {
+		i++
+		scanNumber()
+	}
+	scanIndex()
+
+	if i >= len(runes) {
+		return 0, 0, 0, false
+	}
+
+	return i + 1, explicit, stars, true
+}
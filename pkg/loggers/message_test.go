@@ -0,0 +1,135 @@
+package loggers
+
+import (
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/internal/testutil"
+)
+
+func TestMessageSegments(t *testing.T) {
+	tests := []struct {
+		name    string
+		call    string
+		literal string
+		kinds   []SegmentKind
+	}{
+		{
+			name:    "escape sequences",
+			call:    `slog.Info("a\nbé")`,
+			literal: "a\nbé",
+			kinds:   []SegmentKind{LiteralSegment},
+		},
+		{
+			name:    "raw string",
+			call:    "slog.Info(`a\\nb`)",
+			literal: `a\nb`,
+			kinds:   []SegmentKind{LiteralSegment},
+		},
+		{
+			name:    "constant concatenation",
+			call:    `slog.Info("a" + "B")`,
+			literal: "aB",
+			kinds:   []SegmentKind{LiteralSegment, LiteralSegment},
+		},
+		{
+			name:    "named constant",
+			call:    `slog.Info(prefix + "started")`,
+			literal: "Server started",
+			kinds:   []SegmentKind{LiteralSegment, LiteralSegment},
+		},
+		{
+			name:    "dynamic concatenation",
+			call:    `slog.Info("user " + name)`,
+			literal: "user ",
+			kinds:   []SegmentKind{LiteralSegment, DynamicSegment},
+		},
+		{
+			name:    "sprintf",
+			call:    `slog.Info(fmt.Sprintf("user %s has %5.2f%%", name, 1.5))`,
+			literal: "user  has %",
+			kinds:   []SegmentKind{LiteralSegment, VerbSegment, LiteralSegment, VerbSegment, LiteralSegment},
+		},
+		{
+			name:    "printf method",
+			call:    `log.Printf("%[1]d items", 3)`,
+			literal: " items",
+			kinds:   []SegmentKind{VerbSegment, LiteralSegment},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := `package test
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+)
+
+const prefix = "Server "
+
+var _ = fmt.Sprint
+var _ = log.Print
+var _ = slog.Info
+
+func f(name string) {
+	` + tt.call + `
+}
+`
+			pass, file := testutil.NewPass(t, src)
+			logCalls := NewDetector(pass).DetectLogCalls(file)
+			if len(logCalls) != 1 {
+				t.Fatalf("DetectLogCalls() found %d calls, want 1", len(logCalls))
+			}
+
+			message := logCalls[0].Text
+			if got := message.Literal(); got != tt.literal {
+				t.Errorf("Literal() = %q, want %q", got, tt.literal)
+			}
+
+			if len(message.Segments) != len(tt.kinds) {
+				t.Fatalf("got %d segments, want %d", len(message.Segments), len(tt.kinds))
+			}
+			for i, seg := range message.Segments {
+				if seg.Kind != tt.kinds[i] {
+					t.Errorf("segment %d: kind = %d, want %d", i, seg.Kind, tt.kinds[i])
+				}
+				if seg.Kind == VerbSegment && seg.Expr == nil {
+					t.Errorf("segment %d: verb %s has no argument", i, seg.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestMessageRunePositions(t *testing.T) {
+	src := `package test
+
+import "log/slog"
+
+func f() {
+	slog.Info("a\tZ")
+}
+`
+	pass, file := testutil.NewPass(t, src)
+	logCalls := NewDetector(pass).DetectLogCalls(file)
+	if len(logCalls) != 1 {
+		t.Fatalf("DetectLogCalls() found %d calls, want 1", len(logCalls))
+	}
+
+	runes := logCalls[0].Text.Segments[0].Runes
+	if len(runes) != 3 {
+		t.Fatalf("got %d runes, want 3", len(runes))
+	}
+
+	fset := pass.Fset
+	want := []struct{ col, width int }{{13, 1}, {14, 2}, {16, 1}}
+	for i, r := range runes {
+		pos := fset.Position(r.Pos)
+		if pos.Column != want[i].col || int(r.End-r.Pos) != want[i].width {
+			t.Errorf("rune %q at column %d width %d, want column %d width %d",
+				r.Value, pos.Column, r.End-r.Pos, want[i].col, want[i].width)
+		}
+	}
+}
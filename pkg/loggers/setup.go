@@ -1,49 +1,222 @@
 package loggers
 
-var logMethods = map[string]struct{}{
-	// slog methods
-	"Debug": {},
-	"Info":  {},
-	"Warn":  {},
-	"Error": {},
-
-	// zap sugared methods
-	"Debugf": {},
-	"Infof":  {},
-	"Warnf":  {},
-	"Errorf": {},
-	"Debugw": {},
-	"Infow":  {},
-	"Warnw":  {},
-	"Errorw": {},
-
-	// standard log
-	"Print":   {},
-	"Printf":  {},
-	"Println": {},
-	"Fatal":   {},
-	"Fatalf":  {},
-	"Fatalln": {},
-	"Panic":   {},
-	"Panicf":  {},
-	"Panicln": {},
+type Level string
+
+const (
+	LevelDebug   Level = "debug"
+	LevelInfo    Level = "info"
+	LevelWarn    Level = "warn"
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal"
+	LevelPanic   Level = "panic"
+	LevelUnknown Level = "unknown"
+)
+
+// MethodSpec describes how a logging method takes its arguments.
+type MethodSpec struct {
+	Level Level
+	// index of the message (or format) argument
+	MessageIndex int
+	// message is a printf format followed by its arguments
+	Printf bool
+	// index where attributes start, -1 if the method takes none
+	AttrsIndex int
+}
+
+func leveled(levels map[string]Level, suffix string, spec MethodSpec) map[string]MethodSpec {
+	methods := make(map[string]MethodSpec, len(levels))
+	for name, level := range levels {
+		spec.Level = level
+		methods[name+suffix] = spec
+	}
+	return methods
+}
+
+func merge(tables ...map[string]MethodSpec) map[string]MethodSpec {
+	methods := make(map[string]MethodSpec)
+	for _, table := range tables {
+		for name, spec := range table {
+			methods[name] = spec
+		}
+	}
+	return methods
+}
+
+var slogLevels = map[string]Level{
+	"Debug": LevelDebug,
+	"Info":  LevelInfo,
+	"Warn":  LevelWarn,
+	"Error": LevelError,
+}
+
+var zapLevels = map[string]Level{
+	"Debug":  LevelDebug,
+	"Info":   LevelInfo,
+	"Warn":   LevelWarn,
+	"Error":  LevelError,
+	"DPanic": LevelPanic,
+	"Panic":  LevelPanic,
+	"Fatal":  LevelFatal,
+}
+
+var zerologLevels = map[string]Level{
+	"Trace": LevelDebug,
+	"Debug": LevelDebug,
+	"Info":  LevelInfo,
+	"Warn":  LevelWarn,
+	"Error": LevelError,
+	"Fatal": LevelFatal,
+	"Panic": LevelPanic,
+}
+
+var logrusLevels = map[string]Level{
+	"Trace":   LevelDebug,
+	"Debug":   LevelDebug,
+	"Info":    LevelInfo,
+	"Print":   LevelInfo,
+	"Warn":    LevelWarn,
+	"Warning": LevelWarn,
+	"Error":   LevelError,
+	"Fatal":   LevelFatal,
+	"Panic":   LevelPanic,
+}
+
+var stdLevels = map[string]Level{
+	"Print": LevelInfo,
+	"Fatal": LevelFatal,
+	"Panic": LevelPanic,
+}
+
+var (
+	// slog package functions and *slog.Logger methods
+	slogMethods = merge(
+		leveled(slogLevels, "", MethodSpec{MessageIndex: 0, AttrsIndex: 1}),
+		leveled(slogLevels, "Context", MethodSpec{MessageIndex: 1, AttrsIndex: 2}),
+		map[string]MethodSpec{
+			"Log":      {Level: LevelUnknown, MessageIndex: 2, AttrsIndex: 3},
+			"LogAttrs": {Level: LevelUnknown, MessageIndex: 2, AttrsIndex: 3},
+		},
+	)
+
+	// *zap.Logger methods
+	zapMethods = merge(
+		leveled(zapLevels, "", MethodSpec{MessageIndex: 0, AttrsIndex: 1}),
+		map[string]MethodSpec{
+			"Log": {Level: LevelUnknown, MessageIndex: 1, AttrsIndex: 2},
+		},
+	)
+
+	// *zap.SugaredLogger methods
+	zapSugarMethods = merge(
+		leveled(zapLevels, "", MethodSpec{MessageIndex: 0, AttrsIndex: -1}),
+		leveled(zapLevels, "f", MethodSpec{MessageIndex: 0, Printf: true, AttrsIndex: -1}),
+		leveled(zapLevels, "w", MethodSpec{MessageIndex: 0, AttrsIndex: 1}),
+		leveled(zapLevels, "ln", MethodSpec{MessageIndex: 0, AttrsIndex: -1}),
+		map[string]MethodSpec{
+			"Log":   {Level: LevelUnknown, MessageIndex: 1, AttrsIndex: -1},
+			"Logf":  {Level: LevelUnknown, MessageIndex: 1, Printf: true, AttrsIndex: -1},
+			"Logw":  {Level: LevelUnknown, MessageIndex: 1, AttrsIndex: 2},
+			"Logln": {Level: LevelUnknown, MessageIndex: 1, AttrsIndex: -1},
+		},
+	)
+
+	// *zerolog.Event methods sending the event, whose level and attributes
+	// are set along the chain, and the print methods of zerolog.Logger
+	zerologMethods = map[string]MethodSpec{
+		"Msg":    {Level: LevelUnknown, MessageIndex: 0, AttrsIndex: -1},
+		"Msgf":   {Level: LevelUnknown, MessageIndex: 0, Printf: true, AttrsIndex: -1},
+		"Print":  {Level: LevelDebug, MessageIndex: 0, AttrsIndex: -1},
+		"Printf": {Level: LevelDebug, MessageIndex: 0, Printf: true, AttrsIndex: -1},
+	}
+
+	// logrus package functions and *logrus.Logger and *logrus.Entry
+	// methods, whose fields are added by WithField and WithFields
+	logrusMethods = merge(
+		leveled(logrusLevels, "", MethodSpec{MessageIndex: 0, AttrsIndex: -1}),
+		leveled(logrusLevels, "f", MethodSpec{MessageIndex: 0, Printf: true, AttrsIndex: -1}),
+		leveled(logrusLevels, "ln", MethodSpec{MessageIndex: 0, AttrsIndex: -1}),
+		map[string]MethodSpec{
+			"Log":   {Level: LevelUnknown, MessageIndex: 1, AttrsIndex: -1},
+			"Logf":  {Level: LevelUnknown, MessageIndex: 1, Printf: true, AttrsIndex: -1},
+			"Logln": {Level: LevelUnknown, MessageIndex: 1, AttrsIndex: -1},
+		},
+	)
+
+	// log package functions and *log.Logger methods
+	logMethods = merge(
+		leveled(stdLevels, "", MethodSpec{MessageIndex: 0, AttrsIndex: -1}),
+		leveled(stdLevels, "f", MethodSpec{MessageIndex: 0, Printf: true, AttrsIndex: -1}),
+		leveled(stdLevels, "ln", MethodSpec{MessageIndex: 0, AttrsIndex: -1}),
+		map[string]MethodSpec{
+			"Output": {Level: LevelInfo, MessageIndex: 1, AttrsIndex: -1},
+		},
+	)
+
+	// methods of user-defined logging interfaces, which always take the
+	// message first. Level methods take attributes after it whatever the
+	// level, so Fatal and Panic take them like Info.
+	interfaceMethods = merge(
+		leveled(stdLevels, "", MethodSpec{MessageIndex: 0, AttrsIndex: -1}),
+		leveled(stdLevels, "f", MethodSpec{MessageIndex: 0, Printf: true, AttrsIndex: -1}),
+		leveled(stdLevels, "ln", MethodSpec{MessageIndex: 0, AttrsIndex: -1}),
+		leveled(zapLevels, "", MethodSpec{MessageIndex: 0, AttrsIndex: 1}),
+		leveled(zapLevels, "f", MethodSpec{MessageIndex: 0, Printf: true, AttrsIndex: -1}),
+		leveled(zapLevels, "w", MethodSpec{MessageIndex: 0, AttrsIndex: 1}),
+	)
+)
+
+var methodTables = map[LoggerType]map[string]MethodSpec{
+	SlogLogger:      slogMethods,
+	ZapLogger:       zapMethods,
+	ZapSugarLogger:  zapSugarMethods,
+	ZerologLogger:   zerologMethods,
+	LogrusLogger:    logrusMethods,
+	LogLogger:       logMethods,
+	InterfaceLogger: interfaceMethods,
+}
+
+// LookupMethod returns the spec of a logging method. Methods that don't log,
+// like With or Sync, are not found.
+func LookupMethod(loggerType LoggerType, method string) (MethodSpec, bool) {
+	spec, ok := methodTables[loggerType][method]
+	return spec, ok
 }
 
 type LoggerType string
 
 const (
-	ZapLogger     LoggerType = "zap"
-	LogLogger     LoggerType = "log"
-	SlogLogger    LoggerType = "slog"
-	UnknownLogger LoggerType = "unknown"
+	ZapLogger       LoggerType = "zap"
+	ZapSugarLogger  LoggerType = "zap-sugar"
+	ZerologLogger   LoggerType = "zerolog"
+	LogrusLogger    LoggerType = "logrus"
+	LogLogger       LoggerType = "log"
+	SlogLogger      LoggerType = "slog"
+	InterfaceLogger LoggerType = "interface"
+	WrapperLogger   LoggerType = "wrapper"
+	UnknownLogger   LoggerType = "unknown"
 )
 
-// a slice of logger types associated with their pkg links
-var loggerChecks = []struct {
-	pattern string
-	logType LoggerType
-}{
-	{"log/slog", SlogLogger},
-	{"go.uber.org/zap", ZapLogger},
-	{"log.Logger", LogLogger},
+type loggerName struct {
+	pkgPath string
+	name    string
+}
+
+// named logger types identified by the package path and name of their type
+var namedLoggers = map[loggerName]LoggerType{
+	{"log/slog", "Logger"}:                   SlogLogger,
+	{"go.uber.org/zap", "Logger"}:            ZapLogger,
+	{"go.uber.org/zap", "SugaredLogger"}:     ZapSugarLogger,
+	{"github.com/rs/zerolog", "Logger"}:      ZerologLogger,
+	{"github.com/rs/zerolog", "Event"}:       ZerologLogger,
+	{"github.com/sirupsen/logrus", "Logger"}: LogrusLogger,
+	{"github.com/sirupsen/logrus", "Entry"}:  LogrusLogger,
+	{"log", "Logger"}:                        LogLogger,
+}
+
+// packages whose top-level functions log through a default logger
+var packageLoggers = map[string]LoggerType{
+	"log/slog":                   SlogLogger,
+	"log":                        LogLogger,
+	"github.com/rs/zerolog/log":  ZerologLogger,
+	"github.com/sirupsen/logrus": LogrusLogger,
 }
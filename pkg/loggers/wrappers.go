@@ -0,0 +1,61 @@
+package loggers
+
+import (
+	"go/types"
+)
+
+// SetPrintfWrappers makes the detector treat functions and methods that
+// forward a printf format and its arguments to a logger as logging methods.
+// Names are full names, like example.com/app/log.Infof or
+// (*example.com/app/log.Logger).Debugf.
+func (d *Detector) SetPrintfWrappers(names []string) {
+	d.wrappers = make(map[string]bool, len(names))
+	for _, name := range names {
+		d.wrappers[name] = true
+	}
+}
+
+// SetSlogWrappers makes the detector treat functions and methods that
+// forward a message and slog key/value pairs to a logger, like
+// (*example.com/app/log.Logger).Info(msg string, args ...any), as logging
+// methods.
+func (d *Detector) SetSlogWrappers(names []string) {
+	d.slogWrappers = make(map[string]bool, len(names))
+	for _, name := range names {
+		d.slogWrappers[name] = true
+	}
+}
+
+// wrapperSpec returns the spec of a configured wrapper. Its message or
+// format is the string parameter before the final ...any, and its level is
+// told by its name, like Debugf.
+func (d *Detector) wrapperSpec(fn *types.Func) (MethodSpec, bool) {
+	name := fn.Origin().FullName()
+	printf := d.wrappers[name]
+	if !printf && !d.slogWrappers[name] {
+		return MethodSpec{}, false
+	}
+
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || !sig.Variadic() || sig.Params().Len() < 2 {
+		return MethodSpec{}, false
+	}
+
+	params := sig.Params()
+	message := params.Len() - 2
+	if !isString(params.At(message).Type()) {
+		return MethodSpec{}, false
+	}
+
+	level := LevelUnknown
+	if spec, ok := LookupMethod(InterfaceLogger, fn.Name()); ok {
+		level = spec.Level
+	}
+
+	spec := MethodSpec{Level: level, MessageIndex: message, Printf: printf, AttrsIndex: -1}
+	if !printf {
+		spec.AttrsIndex = message + 1
+	}
+
+	return spec, true
+}
@@ -0,0 +1,111 @@
+package loggers
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+)
+
+const zerologPath = "github.com/rs/zerolog"
+
+// describeEvent fills in the level and attributes of a zerolog event sent by
+// a chain like log.Info().Str("user", id).Msg("login"), recv being the
+// chain before Msg.
+func (d *Detector) describeEvent(logCall *LogCall, recv ast.Expr) {
+	attrs, level := d.eventChain(recv)
+
+	logCall.Attrs = append(logCall.Attrs, attrs...)
+	if logCall.Level == LevelUnknown {
+		logCall.Level = level
+	}
+}
+
+// eventChain returns the fields added to an event along the chain building
+// it, in order, and the level of the method starting the chain. Events held
+// in variables have an unknown level.
+func (d *Detector) eventChain(expr ast.Expr) ([]Attr, Level) {
+	var attrs []Attr
+	level := LevelUnknown
+
+	for {
+		call, ok := ast.Unparen(expr).(*ast.CallExpr)
+		if !ok {
+			break
+		}
+
+		fn := d.funcOf(call.Fun)
+		if fn == nil {
+			break
+		}
+
+		sig := fn.Type().(*types.Signature)
+		if sig.Results().Len() != 1 || !isNamed(sig.Results().At(0).Type(), zerologPath, "Event") {
+			break
+		}
+
+		// level methods of a Logger or the log package start the chain
+		if sig.Recv() == nil || !isNamed(sig.Recv().Type(), zerologPath, "Event") {
+			level = d.eventLevel(fn, call)
+			break
+		}
+
+		if attr, ok := d.eventField(fn, call); ok {
+			attrs = append(attrs, attr)
+		}
+
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		expr = sel.X
+	}
+
+	slices.Reverse(attrs)
+	return attrs, level
+}
+
+func (d *Detector) eventLevel(fn *types.Func, call *ast.CallExpr) Level {
+	if level, ok := zerologLevels[fn.Name()]; ok {
+		return level
+	}
+
+	if fn.Name() == "WithLevel" && len(call.Args) == 1 {
+		return d.constLevel(ZerologLogger, call.Args[0])
+	}
+
+	return LevelUnknown
+}
+
+// eventField parses a call of a field method of an event, like Str("user",
+// id) or Err(err). Methods like Timestamp add no attribute.
+func (d *Detector) eventField(fn *types.Func, call *ast.CallExpr) (Attr, bool) {
+	attr := Attr{Func: fn, Expr: call}
+
+	switch fn.Name() {
+	case "Err":
+		// Err(err) logs under the "error" key
+		if len(call.Args) != 1 {
+			return Attr{}, false
+		}
+		attr.Key = "error"
+		attr.Value = call.Args[0]
+		return attr, true
+	case "Dict":
+		if len(call.Args) != 2 {
+			return Attr{}, false
+		}
+		attr.KeyExpr, attr.Key = call.Args[0], d.constString(call.Args[0])
+		attr.Group, _ = d.eventChain(call.Args[1])
+		return attr, true
+	}
+
+	params := fn.Type().(*types.Signature).Params()
+	if params.Len() != 2 || !isString(params.At(0).Type()) || len(call.Args) != 2 {
+		return Attr{}, false
+	}
+
+	attr.KeyExpr, attr.Key = call.Args[0], d.constString(call.Args[0])
+	attr.Value = call.Args[1]
+
+	return attr, true
+}
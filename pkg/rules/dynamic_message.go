@@ -0,0 +1,545 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// genericNames are names of values too vague to be attribute keys, replaced
+// by the word labeling the value in the message, like user in "user %s".
+var genericNames = []string{"val", "value", "arg", "str", "obj", "data", "msg", "item"}
+
+// messageFillers are words that don't label the value after them, and are
+// dropped from the end of a message once the value is moved out. In and on
+// end phrases like "logged in" too often to be among them.
+var messageFillers = []string{"a", "an", "the", "as", "at", "by", "for", "from", "into", "is", "of", "to", "was", "with", "and", "or"}
+
+// DynamicMessageRule requires the messages of structured loggers to be
+// constant, so that log pipelines can group entries by message. Messages
+// built with fmt.Sprintf, concatenation or a printf-style method get a fix
+// moving their variable parts into attributes. The log package and print-
+// and printf-style methods, which have no attributes, are exempt unless
+// SetCheckUnstructured is set.
+type DynamicMessageRule struct {
+	checkUnstructured bool
+	keyStyle          KeyStyle
+}
+
+func (r *DynamicMessageRule) Name() string {
+	return "no-dynamic-message"
+}
+
+func (r *DynamicMessageRule) Message() string {
+	return "log message should be constant"
+}
+
+// SetCheckUnstructured makes the rule check the log package and print- and
+// printf-style methods too.
+func (r *DynamicMessageRule) SetCheckUnstructured(check bool) {
+	r.checkUnstructured = check
+}
+
+// SetKeyStyle sets the style of the keys the fix infers, snake_case by
+// default.
+func (r *DynamicMessageRule) SetKeyStyle(style KeyStyle) {
+	r.keyStyle = style
+}
+
+func (r *DynamicMessageRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	if !r.checkUnstructured && unstructured(logCall) {
+		return nil
+	}
+
+	how, ok := dynamicMessage(pass, logCall)
+	if !ok {
+		return nil
+	}
+
+	msg := logCall.Message
+	diag := analysis.Diagnostic{
+		Pos:      msg.Pos(),
+		End:      msg.End(),
+		Message:  fmt.Sprintf("%s: it is %s, move the variable parts into attributes", r.Message(), how),
+		Category: r.Name(),
+	}
+
+	if edits, ok := attrsFix(pass, logCall, r.keyStyle); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{
+			{
+				Message:   "Move the variable parts of the message into attributes",
+				TextEdits: edits,
+			},
+		}
+	}
+
+	return []analysis.Diagnostic{diag}
+}
+
+// unstructured reports whether a log call has no attributes to hold the
+// variable parts of its message: calls of the log package, of printf-style
+// methods, and of print-style methods like sugar.Info.
+func unstructured(logCall loggers.LogCall) bool {
+	if logCall.Format {
+		return true
+	}
+	if logCall.Logger == loggers.ZerologLogger {
+		return logCall.Method != "Msg"
+	}
+
+	return logCall.Spec.AttrsIndex < 0
+}
+
+// dynamicMessage tells how the message of a log call varies, if it does.
+func dynamicMessage(pass *analysis.Pass, logCall loggers.LogCall) (string, bool) {
+	spec := logCall.Spec
+	msg := logCall.Message
+
+	if logCall.Format && len(logCall.FormatArgs) > 0 {
+		return "formatted by " + logCall.Method, true
+	}
+	if !logCall.Format && spec.AttrsIndex < 0 && len(logCall.Call.Args) > spec.MessageIndex+1 {
+		return "concatenated from the arguments of " + logCall.Method, true
+	}
+	if isConstant(pass, msg) {
+		return "", false
+	}
+
+	switch e := ast.Unparen(msg).(type) {
+	case *ast.CallExpr:
+		if fn, ok := typeutil.Callee(pass.TypesInfo, e).(*types.Func); ok && fn.Pkg() != nil && fn.Pkg().Path() == "fmt" {
+			return "built with fmt." + fn.Name(), true
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return "concatenated at runtime", true
+		}
+	}
+
+	return "not a constant", true
+}
+
+// messageAttr is a variable part of a message moved into an attribute.
+type messageAttr struct {
+	key   string
+	value ast.Expr
+}
+
+// attrsFix rewrites a log call with a dynamic message to log a constant
+// message with attributes, like slog.Info(fmt.Sprintf("user %s logged in",
+// id)) to slog.Info("user logged in", "user", id). Calls of loggers without
+// attributes, and messages whose parts can't be told, have no fix.
+func attrsFix(pass *analysis.Pass, logCall loggers.LogCall, style KeyStyle) ([]analysis.TextEdit, bool) {
+	format, args, ok := messageTemplate(pass, logCall)
+	if !ok {
+		return nil, false
+	}
+
+	// the format arguments of printf-style methods are replaced too
+	end := logCall.Message.End()
+	if logCall.Format && len(logCall.FormatArgs) > 0 {
+		end = logCall.FormatArgs[len(logCall.FormatArgs)-1].End()
+	}
+
+	return templateEdits(pass, logCall, format, args, end, style)
+}
+
+// templateEdits replaces the message of a log call, up to end, with the
+// constant part of a format, and moves the arguments of the format into
+// attributes of the call, with keys in a style that the call doesn't use
+// yet.
+func templateEdits(pass *analysis.Pass, logCall loggers.LogCall, format string, args []ast.Expr, end token.Pos, style KeyStyle) ([]analysis.TextEdit, bool) {
+	if logCall.Call.Ellipsis.IsValid() {
+		return nil, false
+	}
+
+	var taken []string
+	for _, attr := range logCall.Attrs {
+		if attr.Key != "" {
+			taken = append(taken, attr.Key)
+		}
+	}
+
+	message, attrs, ok := splitTemplate(format, args, style, taken)
+	if !ok {
+		return nil, false
+	}
+	values := make([]string, len(attrs))
+	for i, attr := range attrs {
+		if values[i], ok = sourceText(pass, attr.value); !ok {
+			return nil, false
+		}
+	}
+	start := logCall.Message.Pos()
+
+	var b strings.Builder
+	b.WriteString(strconv.Quote(message))
+	var edits []analysis.TextEdit
+
+	sel, _ := ast.Unparen(logCall.Call.Fun).(*ast.SelectorExpr)
+
+	switch {
+	case logCall.Logger == loggers.ZerologLogger:
+		if sel == nil {
+			return nil, false
+		}
+		var fields strings.Builder
+		for i, attr := range attrs {
+			fmt.Fprintf(&fields, ".%s(%q, %s)", attrConstructor("github.com/rs/zerolog", pass.TypesInfo.TypeOf(attr.value)), attr.key, values[i])
+		}
+		edits = append(edits, analysis.TextEdit{Pos: sel.X.End(), End: sel.X.End(), NewText: []byte(fields.String())})
+		if logCall.Method != "Msg" {
+			edits = append(edits, analysis.TextEdit{Pos: sel.Sel.Pos(), End: sel.Sel.End(), NewText: []byte("Msg")})
+		}
+
+	case logCall.Logger == loggers.ZapLogger || (logCall.Logger == loggers.SlogLogger && logCall.Method == "LogAttrs"):
+		file := fileOf(pass, logCall.Call.Pos())
+		if file == nil {
+			return nil, false
+		}
+		path := "go.uber.org/zap"
+		if logCall.Logger == loggers.SlogLogger {
+			path = "log/slog"
+		}
+		name, importEdit := importName(file, path)
+		if importEdit != nil {
+			edits = append(edits, *importEdit)
+		}
+		for i, attr := range attrs {
+			fmt.Fprintf(&b, ", %s.%s(%q, %s)", name, attrConstructor(path, pass.TypesInfo.TypeOf(attr.value)), attr.key, values[i])
+		}
+
+	case logCall.Spec.AttrsIndex >= 0 && !logCall.Format:
+		writePairs(&b, attrs, values)
+
+	case logCall.Logger == loggers.ZapSugarLogger:
+		// print- and printf-style methods of sugared loggers have ...w
+		// counterparts taking pairs
+		if sel == nil || sel.Sel.Name != logCall.Method {
+			return nil, false
+		}
+		base := strings.TrimSuffix(strings.TrimSuffix(logCall.Method, "f"), "ln")
+		spec, ok := loggers.LookupMethod(loggers.ZapSugarLogger, base+"w")
+		if !ok || spec.AttrsIndex < 0 {
+			return nil, false
+		}
+		edits = append(edits, analysis.TextEdit{Pos: sel.Sel.Pos(), End: sel.Sel.End(), NewText: []byte(base + "w")})
+		writePairs(&b, attrs, values)
+
+	default:
+		return nil, false
+	}
+
+	edits = append(edits, analysis.TextEdit{Pos: start, End: end, NewText: []byte(b.String())})
+	slices.SortFunc(edits, func(a, b analysis.TextEdit) int { return int(a.Pos - b.Pos) })
+
+	return edits, true
+}
+
+func writePairs(b *strings.Builder, attrs []messageAttr, values []string) {
+	for i, attr := range attrs {
+		fmt.Fprintf(b, ", %q, %s", attr.key, values[i])
+	}
+}
+
+// attrConstructor returns the constructor of slog or zap, or the field
+// method of a zerolog event, a value is added with: the one for its type,
+// like zap.String, or the generic one.
+func attrConstructor(pkg string, typ types.Type) string {
+	if name, ok := typedConstructors[pkg][typeName(typ)]; ok {
+		return name
+	}
+
+	return genericConstructors[pkg][0]
+}
+
+// messageTemplate returns the message of a log call as a printf format and
+// its arguments: the format of a printf-style method or of fmt.Sprintf, or
+// a concatenation with %v for the operands that aren't constant.
+func messageTemplate(pass *analysis.Pass, logCall loggers.LogCall) (string, []ast.Expr, bool) {
+	msg := logCall.Message
+
+	if logCall.Format {
+		format, ok := constString(pass, msg)
+		return format, logCall.FormatArgs, ok
+	}
+
+	// print-style methods concatenate their arguments
+	if logCall.Spec.AttrsIndex < 0 && logCall.Logger != loggers.ZerologLogger && len(logCall.Call.Args) > logCall.Spec.MessageIndex+1 {
+		return "", nil, false
+	}
+
+	switch e := ast.Unparen(msg).(type) {
+	case *ast.CallExpr:
+		fn, ok := typeutil.Callee(pass.TypesInfo, e).(*types.Func)
+		if !ok || fn.FullName() != "fmt.Sprintf" || len(e.Args) == 0 || e.Ellipsis.IsValid() {
+			return "", nil, false
+		}
+		format, ok := constString(pass, e.Args[0])
+		return format, e.Args[1:], ok
+
+	case *ast.BinaryExpr:
+		var (
+			format strings.Builder
+			args   []ast.Expr
+		)
+		for _, operand := range concatOperands(pass, e) {
+			if s, ok := constString(pass, operand); ok {
+				format.WriteString(strings.ReplaceAll(s, "%", "%%"))
+			} else {
+				format.WriteString("%v")
+				args = append(args, operand)
+			}
+		}
+		return format.String(), args, len(args) > 0
+	}
+
+	return "", nil, false
+}
+
+// concatOperands flattens a string concatenation. Constant parts are kept
+// whole.
+func concatOperands(pass *analysis.Pass, expr ast.Expr) []ast.Expr {
+	e, ok := ast.Unparen(expr).(*ast.BinaryExpr)
+	if !ok || e.Op != token.ADD || isConstant(pass, e) {
+		return []ast.Expr{expr}
+	}
+
+	return append(concatOperands(pass, e.X), concatOperands(pass, e.Y)...)
+}
+
+func constString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+// splitTemplate splits a format into the constant message left once its
+// verbs, and the labels and quotes around them, are removed, and the
+// attributes of its arguments. Keys repeating one another, or one of the
+// taken keys, are numbered.
+func splitTemplate(format string, args []ast.Expr, style KeyStyle, taken []string) (string, []messageAttr, bool) {
+	verbs, err := parsePrintf(format)
+	if err != nil {
+		return "", nil, false
+	}
+
+	var (
+		message strings.Builder
+		attrs   []messageAttr
+		keys    = make(map[string]int)
+		last    = 0
+		// filler word right before the last value
+		dangling string
+	)
+
+	if style == "" {
+		style = SnakeCase
+	}
+	for _, key := range taken {
+		keys[key]++
+	}
+
+	for _, v := range verbs {
+		if len(v.stars) > 0 || v.indexed || v.verb == 'w' {
+			return "", nil, false
+		}
+
+		literal := format[last:v.pos]
+		last = v.pos + len(v.text)
+
+		if v.verb == '%' {
+			message.WriteString(literal + "%")
+			continue
+		}
+		if v.arg >= len(args) {
+			return "", nil, false
+		}
+
+		// quotes and brackets around the value go with it
+		if n := len(literal); n > 0 && last < len(format) {
+			if closer, ok := map[byte]byte{'\'': '\'', '"': '"', '(': ')', '[': ']', '<': '>'}[literal[n-1]]; ok && format[last] == closer {
+				literal = literal[:n-1]
+				last++
+			}
+		}
+
+		literal, label := cutLabel(literal)
+		message.WriteString(literal)
+		dangling = fillerBefore(literal)
+
+		key := attrKey(args[v.arg], label, style)
+		for base := key; keys[key] > 0; {
+			keys[base]++
+			key = convertKey(fmt.Sprintf("%s_%d", base, keys[base]), style)
+		}
+		keys[key]++
+		attrs = append(attrs, messageAttr{key: key, value: args[v.arg]})
+	}
+
+	if len(attrs) != len(args) {
+		return "", nil, false
+	}
+	message.WriteString(format[last:])
+
+	// a filler before a value ending the message, like from in "loaded from
+	// %s", is left dangling
+	if strings.Trim(format[last:], " .:;,=-") != "" {
+		dangling = ""
+	}
+	text := cleanMessage(message.String(), dangling)
+	if text == "" {
+		return "", nil, false
+	}
+
+	return text, attrs, true
+}
+
+// cutLabel returns the word labeling the value after a literal, like user in
+// "user %s" or "user=%s". Labels written as key=value or key: value are cut
+// from the literal.
+func cutLabel(literal string) (string, string) {
+	trimmed := strings.TrimRight(literal, " ")
+	cut := false
+	if rest, ok := strings.CutSuffix(trimmed, "="); ok {
+		trimmed, cut = rest, true
+	} else if rest, ok := strings.CutSuffix(trimmed, ":"); ok {
+		trimmed, cut = rest, true
+	} else if trimmed == literal {
+		// a value right after a word, like id%d, has no label
+		return literal, ""
+	}
+
+	start := strings.LastIndexFunc(trimmed, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	}) + 1
+	word := strings.ToLower(trimmed[start:])
+
+	if word == "" || slices.Contains(messageFillers, word) {
+		return literal, ""
+	}
+	if cut {
+		return trimmed[:start], word
+	}
+
+	return literal, word
+}
+
+// attrKey infers the key of an attribute from the name of its value in a
+// key style, like user_id for userID in snake_case or path for r.URL.Path.
+// Short and generic names, and values without a name, take the label of the
+// value in the message instead.
+func attrKey(value ast.Expr, label string, style KeyStyle) string {
+	name := exprName(value)
+	if label != "" && (len(name) <= 2 || slices.Contains(genericNames, strings.ToLower(name))) {
+		return label
+	}
+	if name == "" {
+		return "value"
+	}
+
+	return convertKey(name, style)
+}
+
+// exprName returns the name of the variable, field or function an
+// expression reads, like Path for r.URL.Path or Name for u.GetName().
+func exprName(expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.StarExpr:
+		return exprName(e.X)
+	case *ast.IndexExpr:
+		return exprName(e.X)
+	case *ast.CallExpr:
+		name := funcName(e)
+		if rest, ok := strings.CutPrefix(name, "Get"); ok && rest != "" {
+			return rest
+		}
+		return name
+	}
+
+	return ""
+}
+
+// cleanMessage collapses the spaces left by removed values, and trims
+// separators, and the dangling filler word if any, from the end of a
+// message.
+func cleanMessage(message, dangling string) string {
+	message = strings.TrimRight(strings.Join(strings.Fields(message), " "), " :;,=-")
+
+	if dangling != "" {
+		if rest, ok := strings.CutSuffix(message, " "+dangling); ok {
+			message = strings.TrimRight(rest, " :;,=-")
+		}
+	}
+
+	return message
+}
+
+// fillerBefore returns the filler word a literal ends with, before the value
+// following it.
+func fillerBefore(literal string) string {
+	if !strings.HasSuffix(literal, " ") {
+		return ""
+	}
+
+	fields := strings.Fields(literal)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	word := fields[len(fields)-1]
+	if !slices.Contains(messageFillers, strings.ToLower(word)) {
+		return ""
+	}
+
+	return word
+}
+
+// sourceText returns the source of an expression as written in its file,
+// printing it back would abbreviate literals.
+func sourceText(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	file := pass.Fset.File(expr.Pos())
+	if file == nil || pass.ReadFile == nil {
+		return "", false
+	}
+
+	content, err := pass.ReadFile(file.Name())
+	if err != nil {
+		return "", false
+	}
+
+	start, end := file.Offset(expr.Pos()), file.Offset(expr.End())
+	if end > len(content) {
+		return "", false
+	}
+
+	return string(content[start:end]), true
+}
+
+// fileOf returns the file of a package containing pos.
+func fileOf(pass *analysis.Pass, pos token.Pos) *ast.File {
+	for _, file := range pass.Files {
+		if file.FileStart <= pos && pos <= file.FileEnd {
+			return file
+		}
+	}
+
+	return nil
+}
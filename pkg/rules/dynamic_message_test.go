@@ -0,0 +1,217 @@
+package rules
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+)
+
+const dynamicMessageSrc = `package test
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+)
+
+var (
+	_ = fmt.Sprint
+	_ = log.Print
+	_ = slog.Info
+	_ = zap.L
+)
+
+type request struct {
+	Path string
+}
+
+func f(logger *zap.Logger, sugar *zap.SugaredLogger, zl zerolog.Logger, id string, userName string, count int, r request, err error) {
+	BODY
+}
+`
+
+func TestDynamicMessageRule(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		unstructured bool
+		want         string
+	}{
+		{
+			name: "sprintf",
+			body: `slog.Info(fmt.Sprintf("user %s logged in", id))`,
+			want: "it is built with fmt.Sprintf",
+		},
+		{
+			name: "concatenation",
+			body: `logger.Info("user " + id + " logged in")`,
+			want: "it is concatenated at runtime",
+		},
+		{
+			name: "variable",
+			body: `msg := "user " + id; zl.Info().Msg(msg)`,
+			want: "it is not a constant",
+		},
+		{
+			name: "sugared with pairs",
+			body: `sugar.Infow(fmt.Sprintf("user %s", id), "count", count)`,
+			want: "it is built with fmt.Sprintf",
+		},
+		{
+			name: "constant",
+			body: `const prefix = "user "; slog.Info(prefix + "logged in", "user", id)`,
+		},
+		{
+			name: "printf methods are exempt",
+			body: `log.Printf("user %s logged in", id); sugar.Infof("user %s", id); zl.Info().Msgf("user %s", id)`,
+		},
+		{
+			name: "print methods are exempt",
+			body: `log.Print("user " + id); sugar.Info("user ", id)`,
+		},
+		{
+			name:         "printf methods checked",
+			body:         `sugar.Infof("user %s", id)`,
+			unstructured: true,
+			want:         "it is formatted by Infof",
+		},
+		{
+			name:         "print methods checked",
+			body:         `log.Print("user ", id)`,
+			unstructured: true,
+			want:         "it is concatenated from the arguments of Print",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &DynamicMessageRule{}
+			rule.SetCheckUnstructured(tt.unstructured)
+
+			diagnostics := checkSource(t, rule, strings.Replace(dynamicMessageSrc, "BODY", tt.body, 1))
+
+			if tt.want == "" {
+				if len(diagnostics) != 0 {
+					t.Fatalf("Check() reported %q, want nothing", diagnostics[0].Message)
+				}
+				return
+			}
+
+			if len(diagnostics) != 1 {
+				t.Fatalf("Check() reported %d diagnostics, want 1", len(diagnostics))
+			}
+			if !strings.Contains(diagnostics[0].Message, tt.want) {
+				t.Errorf("Check() message = %q, want %q", diagnostics[0].Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestDynamicMessageRuleFix(t *testing.T) {
+	tests := []struct {
+		name  string
+		style KeyStyle
+		body  string
+		want  string
+	}{
+		{
+			name: "slog sprintf",
+			body: `slog.Info(fmt.Sprintf("user %s logged in", id))`,
+			want: `slog.Info("user logged in", "user", id)`,
+		},
+		{
+			name: "identifier names",
+			body: `slog.Warn(fmt.Sprintf("deleting %d files of %s", count, userName), "path", r.Path)`,
+			want: `slog.Warn("deleting files", "count", count, "user_name", userName, "path", r.Path)`,
+		},
+		{
+			name: "labels and quotes",
+			body: `slog.Error(fmt.Sprintf("request failed: path='%s' err=%v", r.Path, err))`,
+			want: `slog.Error("request failed", "path", r.Path, "err", err)`,
+		},
+		{
+			name: "composite literals",
+			body: `slog.Info(fmt.Sprintf("request %v", request{Path: id}))`,
+			want: `slog.Info("request", "request", request{Path: id})`,
+		},
+		{
+			name: "function literals",
+			body: `logger.Info(fmt.Sprintf("user %s logged in", func() string { return id }()))`,
+			want: `logger.Info("user logged in", zap.String("user", func() string { return id }()))`,
+		},
+		{
+			name: "concatenation",
+			body: `slog.Info("user " + id + " logged in")`,
+			want: `slog.Info("user logged in", "user", id)`,
+		},
+		{
+			name: "zap fields",
+			body: `logger.Info(fmt.Sprintf("user %s logged in", id))`,
+			want: `logger.Info("user logged in", zap.String("user", id))`,
+		},
+		{
+			name: "sugared printf",
+			body: `sugar.Infof("loaded %d files from %s", count, r.Path)`,
+			want: `sugar.Infow("loaded files", "count", count, "path", r.Path)`,
+		},
+		{
+			name: "zerolog",
+			body: `zl.Info().Int("attempt", 1).Msgf("user %s logged in (%d)", userName, count)`,
+			want: `zl.Info().Int("attempt", 1).Str("user_name", userName).Int("count", count).Msg("user logged in")`,
+		},
+		{
+			name: "duplicate keys",
+			body: `slog.Info(fmt.Sprintf("%v then %v", r.Path, r.Path))`,
+			want: `slog.Info("then", "path", r.Path, "path_2", r.Path)`,
+		},
+		{
+			name: "keys of the call",
+			body: `slog.Info(fmt.Sprintf("took %d", count), "count", 1)`,
+			want: `slog.Info("took", "count_2", count, "count", 1)`,
+		},
+		{
+			name:  "key style",
+			style: CamelCase,
+			body:  `slog.Info(fmt.Sprintf("deleting files of %s", userName))`,
+			want:  `slog.Info("deleting files", "userName", userName)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := strings.Replace(dynamicMessageSrc, "BODY", tt.body, 1)
+			pass, file := newTestPass(t, src)
+
+			rule := &DynamicMessageRule{}
+			rule.SetCheckUnstructured(true)
+			rule.SetKeyStyle(tt.style)
+
+			var diagnostics []string
+			for _, logCall := range loggers.NewDetector(pass).DetectLogCalls(file) {
+				for _, diag := range rule.Check(pass, logCall) {
+					if len(diag.SuggestedFixes) != 1 {
+						t.Fatalf("Check() reported %q without a fix", diag.Message)
+					}
+					diagnostics = append(diagnostics, applyFix(pass, src, diag.SuggestedFixes[0]))
+				}
+			}
+
+			if len(diagnostics) != 1 {
+				t.Fatalf("Check() reported %d diagnostics, want 1", len(diagnostics))
+			}
+
+			formatted, err := format.Source([]byte(diagnostics[0]))
+			if err != nil {
+				t.Fatalf("fixed source doesn't parse: %v\n%s", err, diagnostics[0])
+			}
+			if want := strings.Replace(dynamicMessageSrc, "BODY", tt.want, 1); string(formatted) != want {
+				t.Errorf("fixed source =\n%s\nwant\n%s", formatted, want)
+			}
+		})
+	}
+}
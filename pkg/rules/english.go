@@ -20,8 +20,8 @@ func (r *EnglishOnlyRule) Message() string {
 }
 
 func (r *EnglishOnlyRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
-	message, ok := loggers.ExtractStringLit(logCall.Message)
-	if !ok {
+	message := logCall.Text.Literal()
+	if message == "" {
 		return nil
 	}
 
@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/annotations"
+	"github.com/hel1th/loglinter/pkg/internal/testutil"
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"github.com/hel1th/loglinter/pkg/taint"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// factKey identifies a fact like the checker does, by object and type.
+type factKey struct {
+	obj types.Object
+	typ reflect.Type
+}
+
+// newTestPass returns a pass over src with the SSA form of the package and
+// object facts, as the rules get them from their analyzer.
+func newTestPass(t *testing.T, src string) (*analysis.Pass, *ast.File) {
+	t.Helper()
+
+	pass, file := testutil.NewPass(t, src)
+
+	ssaPkg := taint.BuildPackage(ssa.NewProgram(pass.Fset, ssa.GlobalDebug), pass.Pkg, pass.Files, pass.TypesInfo)
+	pass.ResultOf = map[*analysis.Analyzer]any{taint.SSAAnalyzer: ssaPkg}
+
+	facts := make(map[factKey]analysis.Fact)
+	pass.ExportObjectFact = func(obj types.Object, fact analysis.Fact) {
+		facts[factKey{obj, reflect.TypeOf(fact)}] = fact
+	}
+	pass.ImportObjectFact = func(obj types.Object, fact analysis.Fact) bool {
+		stored, ok := facts[factKey{obj, reflect.TypeOf(fact)}]
+		if ok {
+			reflect.ValueOf(fact).Elem().Set(reflect.ValueOf(stored).Elem())
+		}
+		return ok
+	}
+
+	return pass, file
+}
+
+// checkSource runs a rule over every log call of a source file.
+func checkSource(t *testing.T, rule Rule, src string) []analysis.Diagnostic {
+	t.Helper()
+
+	_, diagnostics := checkSourcePass(t, rule, src)
+	return diagnostics
+}
+
+// checkSourcePass is checkSource returning the pass too, for applying fixes.
+func checkSourcePass(t *testing.T, rule Rule, src string) (*analysis.Pass, []analysis.Diagnostic) {
+	t.Helper()
+
+	pass, file := newTestPass(t, src)
+	annotations.Export(pass, annotations.Options{})
+
+	var diagnostics []analysis.Diagnostic
+	for _, logCall := range loggers.NewDetector(pass).DetectLogCalls(file) {
+		diagnostics = append(diagnostics, rule.Check(pass, logCall)...)
+	}
+
+	return pass, diagnostics
+}
+
+// applyFix applies the edits of a suggested fix to the source of a test pass.
+func applyFix(pass *analysis.Pass, src string, fix analysis.SuggestedFix) string {
+	base := pass.Fset.File(pass.Files[0].Pos()).Base()
+
+	edits := slices.Clone(fix.TextEdits)
+	slices.SortFunc(edits, func(a, b analysis.TextEdit) int { return int(b.Pos - a.Pos) })
+
+	for _, edit := range edits {
+		start, end := int(edit.Pos)-base, int(edit.End)-base
+		src = src[:start] + string(edit.NewText) + src[end:]
+	}
+
+	return src
+}
@@ -0,0 +1,161 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"slices"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"github.com/hel1th/loglinter/pkg/taint"
+	"golang.org/x/tools/go/analysis"
+)
+
+// DefaultUntrustedSources are functions and struct fields whose values come
+// from clients when no sources are configured.
+var DefaultUntrustedSources = []string{
+	"(*net/http.Request).FormValue",
+	"(*net/http.Request).PostFormValue",
+	"(*net/http.Request).UserAgent",
+	"(*net/http.Request).Referer",
+	"(*net/http.Request).Cookie",
+	"(net/http.Header).Get",
+	"(net/http.Header).Values",
+	"(net/http.Request).Header",
+	"(net/http.Request).Form",
+	"(net/http.Request).PostForm",
+	"(net/http.Request).MultipartForm",
+	"(net/http.Request).URL",
+	"(net/http.Request).Host",
+	"(net/http.Request).RequestURI",
+	"(net/http.Request).Body",
+	"(net/http.Cookie).Value",
+}
+
+// DefaultSanitizers are functions whose results can't forge log lines, as
+// they escape line breaks.
+var DefaultSanitizers = []string{
+	"strconv.Quote",
+	"strconv.QuoteToASCII",
+	"strconv.QuoteToGraphic",
+	"net/url.QueryEscape",
+	"net/url.PathEscape",
+	"html.EscapeString",
+}
+
+// LogInjectionRule reports untrusted input, like request parameters and
+// headers, flowing into the message of a log call, where line breaks in it
+// can forge log lines. Attributes are escaped by structured handlers and
+// aren't reported, and neither are values passed through a sanitizer.
+type LogInjectionRule struct {
+	sources    []taint.Source
+	sanitizers []string
+
+	// taint results of the last package checked
+	taintPass   *analysis.Pass
+	taintResult *taint.Result
+}
+
+func (r *LogInjectionRule) Name() string {
+	return "no-log-injection"
+}
+
+func (r *LogInjectionRule) Message() string {
+	return "log message may be forged by untrusted input"
+}
+
+// SetSources sets the untrusted sources. Nil means DefaultUntrustedSources.
+func (r *LogInjectionRule) SetSources(sources []taint.Source) {
+	r.sources = sources
+}
+
+// SetSanitizers adds escaping functions to DefaultSanitizers.
+func (r *LogInjectionRule) SetSanitizers(sanitizers []string) {
+	r.sanitizers = sanitizers
+}
+
+func (r *LogInjectionRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	for _, flow := range r.taintFlows(pass, logCall.Call) {
+		if flow.Arg >= len(logCall.Call.Args) {
+			continue
+		}
+		arg := logCall.Call.Args[flow.Arg]
+
+		// flags and numbers can't break lines
+		if !mayHoldSecret(pass.TypesInfo.TypeOf(arg)) {
+			continue
+		}
+
+		sink := sinkName(logCall, flow.Arg)
+		if sink != "message" && sink != "format arguments" {
+			continue
+		}
+
+		// %q and hex or numeric verbs escape line breaks
+		if sink == "format arguments" && escapedVerbs(logCall.Text, func(seg loggers.Segment) bool { return seg.Expr == arg }) ||
+			sink == "message" && escapedVerbs(logCall.Text, func(loggers.Segment) bool { return true }) {
+			continue
+		}
+
+		diagnostics = append(diagnostics, analysis.Diagnostic{
+			Pos:      arg.Pos(),
+			End:      arg.End(),
+			Message:  fmt.Sprintf("%s: %s flows into the %s (%s -> %s), pass it as an attribute or quote it", r.Message(), flow.Source(), sink, flow, sink),
+			Category: r.Name(),
+		})
+	}
+
+	return diagnostics
+}
+
+// taintFlows runs the taint analysis once per package. It needs the result
+// of taint.SSAAnalyzer and is skipped without it.
+func (r *LogInjectionRule) taintFlows(pass *analysis.Pass, call *ast.CallExpr) []taint.Flow {
+	if r.taintPass != pass {
+		r.taintPass = pass
+		r.taintResult = nil
+
+		if pkg, ok := pass.ResultOf[taint.SSAAnalyzer].(*taint.SSA); ok {
+			r.taintResult = taint.Analyze(pkg, pass.TypesInfo, taint.Config{
+				Sources:    r.untrustedSources(),
+				Sanitizers: slices.Concat(DefaultSanitizers, r.sanitizers),
+			})
+		}
+	}
+
+	return r.taintResult.Flows(call)
+}
+
+func (r *LogInjectionRule) untrustedSources() []taint.Source {
+	if r.sources != nil {
+		return r.sources
+	}
+
+	sources := make([]taint.Source, len(DefaultUntrustedSources))
+	for i, s := range DefaultUntrustedSources {
+		sources[i] = taint.Source{Func: s}
+	}
+
+	return sources
+}
+
+// escapedVerbs reports whether the message pieces selected by match are all
+// verbs printing their argument escaped, like %q, or as hex or a number.
+// Dynamic pieces are printed as they are.
+func escapedVerbs(text loggers.Message, match func(loggers.Segment) bool) bool {
+	found := false
+
+	for _, seg := range text.Segments {
+		if seg.Kind == loggers.LiteralSegment || !match(seg) {
+			continue
+		}
+		if seg.Kind != loggers.VerbSegment || seg.Text == "" || !strings.ContainsRune("qxXdboOeEfFgGU", rune(seg.Text[len(seg.Text)-1])) {
+			return false
+		}
+		found = true
+	}
+
+	return found
+}
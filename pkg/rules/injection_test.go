@@ -0,0 +1,122 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogInjectionRule(t *testing.T) {
+	rule := &LogInjectionRule{}
+	rule.SetSanitizers([]string{"test.escape"})
+
+	tests := []struct {
+		name     string
+		body     string
+		wantPath string
+	}{
+		{
+			name:     "form value through sprintf",
+			body:     `log.Print(fmt.Sprintf("login of %s", r.FormValue("user")))`,
+			wantPath: "(*net/http.Request).FormValue -> fmt.Sprintf -> message",
+		},
+		{
+			name:     "form value concatenated",
+			body:     `slog.Info("login of " + r.FormValue("user"))`,
+			wantPath: "(*net/http.Request).FormValue -> string concatenation -> message",
+		},
+		{
+			name:     "format argument",
+			body:     `log.Printf("agent %s", r.UserAgent())`,
+			wantPath: "(*net/http.Request).UserAgent flows into the format arguments",
+		},
+		{
+			name:     "header",
+			body:     `id := r.Header.Get("X-Request-Id"); log.Println("request", id)`,
+			wantPath: "(net/http.Header).Get flows into the message",
+		},
+		{
+			name:     "request field",
+			body:     `log.Printf("host %s", r.Host)`,
+			wantPath: "r.Host flows into the format arguments",
+		},
+		{
+			name:     "decoded body",
+			body:     `var in input; json.NewDecoder(r.Body).Decode(&in); log.Printf("hello %s", in.Name)`,
+			wantPath: "r.Body -> encoding/json.NewDecoder -> (*encoding/json.Decoder).Decode -> format arguments",
+		},
+		{
+			name: "attribute",
+			body: `slog.Info("login", "user", r.FormValue("user"), slog.String("agent", r.UserAgent()))`,
+		},
+		{
+			name: "quoted",
+			body: `log.Printf("login of %s", strconv.Quote(r.FormValue("user")))`,
+		},
+		{
+			name: "quoting verb",
+			body: `log.Printf("login of %q", r.FormValue("user")); log.Print(fmt.Sprintf("agent %q", r.UserAgent()))`,
+		},
+		{
+			name:     "quoting verb beside another",
+			body:     `u := r.FormValue("user"); log.Printf("login of %q (%s)", u, u)`,
+			wantPath: "(*net/http.Request).FormValue flows into the format arguments",
+		},
+		{
+			name: "configured sanitizer",
+			body: `log.Print("login of " + escape(r.FormValue("user")))`,
+		},
+		{
+			name: "number",
+			body: `n, _ := strconv.Atoi(r.FormValue("n")); log.Printf("page %d", n)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := `package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type input struct {
+	Name string
+}
+
+func escape(s string) string { return s }
+
+var (
+	_ = fmt.Sprint
+	_ = json.Marshal
+	_ = log.Print
+	_ = slog.Info
+	_ = strconv.Quote
+)
+
+func f(r *http.Request) {
+	` + tt.body + `
+}
+`
+			diagnostics := checkSource(t, rule, src)
+
+			if tt.wantPath == "" {
+				if len(diagnostics) != 0 {
+					t.Fatalf("Check() reported %q, want nothing", diagnostics[0].Message)
+				}
+				return
+			}
+
+			if len(diagnostics) != 1 {
+				t.Fatalf("Check() reported %d diagnostics, want 1", len(diagnostics))
+			}
+			if !strings.Contains(diagnostics[0].Message, tt.wantPath) {
+				t.Errorf("Check() message = %q, want path %q", diagnostics[0].Message, tt.wantPath)
+			}
+		})
+	}
+}
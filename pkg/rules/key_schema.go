@@ -0,0 +1,175 @@
+package rules
+
+import (
+	"fmt"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+// valueKinds are the kinds of values a key schema can expect. Any accepts
+// every value.
+var valueKinds = []string{"string", "int", "float", "bool", "duration", "time", "error", "any"}
+
+// KeySchemaRule checks attribute keys against a schema listing the allowed
+// keys and the kind of value each takes, like int for duration_ms. Keys
+// nested in groups are listed by their dotted path, like http.method.
+// Without a schema the rule reports nothing.
+type KeySchemaRule struct {
+	schema map[string]string
+}
+
+func (r *KeySchemaRule) Name() string {
+	return "attr-key-schema"
+}
+
+func (r *KeySchemaRule) Message() string {
+	return "attribute doesn't match the key schema"
+}
+
+// SetSchema sets the allowed keys and the kinds of their values.
+func (r *KeySchemaRule) SetSchema(schema map[string]string) error {
+	for key, kind := range schema {
+		if !slices.Contains(valueKinds, kind) {
+			return fmt.Errorf("key schema: key %q has unknown kind %q, want one of %s", key, kind, strings.Join(valueKinds, ", "))
+		}
+	}
+
+	r.schema = schema
+	return nil
+}
+
+func (r *KeySchemaRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	return r.checkAttrs(pass, logCall.Attrs)
+}
+
+// CheckPackage checks the keys added by With calls.
+func (r *KeySchemaRule) CheckPackage(pass *analysis.Pass) []analysis.Diagnostic {
+	if len(r.schema) == 0 {
+		return nil
+	}
+
+	var diagnostics []analysis.Diagnostic
+	for _, withCall := range detectWithCalls(pass) {
+		diagnostics = append(diagnostics, r.checkAttrs(pass, withCall.Attrs)...)
+	}
+
+	return diagnostics
+}
+
+func (r *KeySchemaRule) checkAttrs(pass *analysis.Pass, attrs []loggers.Attr) []analysis.Diagnostic {
+	if len(r.schema) == 0 {
+		return nil
+	}
+
+	var diagnostics []analysis.Diagnostic
+
+	walkAttrs(attrs, "", func(attr loggers.Attr, path string) {
+		// groups are checked by their keys
+		if attr.Key == "" || len(attr.Group) > 0 {
+			return
+		}
+
+		node := attr.Expr
+		if attr.KeyExpr != nil {
+			node = attr.KeyExpr
+		}
+
+		kind, ok := r.schema[path]
+		if !ok {
+			diag := analysis.Diagnostic{
+				Pos:      node.Pos(),
+				End:      node.End(),
+				Message:  fmt.Sprintf("%s: key %q is not in the schema", r.Message(), path),
+				Category: r.Name(),
+			}
+
+			if known, ok := r.similarKey(path); ok && attr.KeyExpr != nil {
+				diag.Message += fmt.Sprintf(", use %q", known)
+				// members of a group keep their group
+				group := strings.TrimSuffix(path, attr.Key)
+				renamed, inGroup := strings.CutPrefix(known, group)
+				if edit, ok := renameKeyEdit(attr.KeyExpr, renamed); ok && inGroup {
+					diag.SuggestedFixes = []analysis.SuggestedFix{{
+						Message:   fmt.Sprintf("Rename the key to %q", known),
+						TextEdits: []analysis.TextEdit{edit},
+					}}
+				}
+			}
+
+			diagnostics = append(diagnostics, diag)
+			return
+		}
+
+		if attr.Value == nil || kind == "any" {
+			return
+		}
+		if got := valueKind(pass.TypesInfo.TypeOf(attr.Value)); got != "" && got != kind {
+			diagnostics = append(diagnostics, analysis.Diagnostic{
+				Pos:      attr.Expr.Pos(),
+				End:      attr.Expr.End(),
+				Message:  fmt.Sprintf("%s: key %q takes %s values, not %s", r.Message(), path, kind, got),
+				Category: r.Name(),
+			})
+		}
+	})
+
+	return diagnostics
+}
+
+// similarKey returns the schema key a key differs from only by style, like
+// user_id for userID.
+func (r *KeySchemaRule) similarKey(key string) (string, bool) {
+	words := strings.Join(keyWords(key), " ")
+
+	for _, known := range slices.Sorted(maps.Keys(r.schema)) {
+		if strings.Join(keyWords(known), " ") == words {
+			return known, true
+		}
+	}
+
+	return "", false
+}
+
+// valueKind returns the schema kind of values of a type, or "" for types
+// like any whose values may be of any kind.
+func valueKind(typ types.Type) string {
+	if typ == nil {
+		return ""
+	}
+
+	switch qualifiedName(typ) {
+	case "time.Duration":
+		return "duration"
+	case "time.Time":
+		return "time"
+	}
+
+	if types.Implements(typ, errorType) {
+		return "error"
+	}
+
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return ""
+	}
+
+	switch info := basic.Info(); {
+	case info&types.IsBoolean != 0:
+		return "bool"
+	case info&types.IsInteger != 0:
+		return "int"
+	case info&types.IsFloat != 0:
+		return "float"
+	case info&types.IsString != 0:
+		return "string"
+	}
+
+	return ""
+}
+
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
@@ -0,0 +1,240 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"github.com/hel1th/loglinter/pkg/semconv"
+	"golang.org/x/tools/go/analysis"
+)
+
+// KeyStyle is a naming convention for attribute keys.
+type KeyStyle string
+
+const (
+	// user_id
+	SnakeCase KeyStyle = "snake_case"
+	// userId or userID
+	CamelCase KeyStyle = "camelCase"
+	// http.status_code: lowercase namespaces separated by dots, words
+	// within them by underscores, as in OpenTelemetry
+	Dotted KeyStyle = "dotted"
+)
+
+var keyStylePatterns = map[KeyStyle]*regexp.Regexp{
+	SnakeCase: regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`),
+	CamelCase: regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	Dotted:    regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*(\.[a-z0-9]+(_[a-z0-9]+)*)*$`),
+}
+
+// KeyStyleRule requires the attribute keys of slog, zap, logrus and zerolog
+// calls, and of With calls, to follow one naming convention, so that the
+// same field isn't logged as userID by one service and user_id by another.
+// Keys written as literals get a fix renaming them.
+type KeyStyleRule struct {
+	style KeyStyle
+	// semantic conventions otel-semconv enforces, whose keys are accepted
+	// whatever the style
+	conventions *semconv.Conventions
+}
+
+func (r *KeyStyleRule) Name() string {
+	return "attr-key-style"
+}
+
+func (r *KeyStyleRule) Message() string {
+	return "attribute key doesn't follow the key style"
+}
+
+// SetStyle sets the style keys must follow, snake_case by default.
+func (r *KeyStyleRule) SetStyle(style string) error {
+	if style == "" {
+		style = string(SnakeCase)
+	}
+	if _, ok := keyStylePatterns[KeyStyle(style)]; !ok {
+		return fmt.Errorf("unknown key style %q, want %s, %s or %s", style, SnakeCase, CamelCase, Dotted)
+	}
+
+	r.style = KeyStyle(style)
+	return nil
+}
+
+// SetConventions accepts the keys of the semantic conventions, and leaves
+// keys they rename to otel-semconv, so that both rules don't suggest
+// different keys.
+func (r *KeyStyleRule) SetConventions(conventions *semconv.Conventions) {
+	r.conventions = conventions
+}
+
+func (r *KeyStyleRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	return r.checkAttrs(logCall.Attrs)
+}
+
+// CheckPackage checks the keys added by With calls.
+func (r *KeyStyleRule) CheckPackage(pass *analysis.Pass) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	for _, withCall := range detectWithCalls(pass) {
+		diagnostics = append(diagnostics, r.checkAttrs(withCall.Attrs)...)
+	}
+
+	return diagnostics
+}
+
+func (r *KeyStyleRule) checkAttrs(attrs []loggers.Attr) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	style := r.style
+	if style == "" {
+		style = SnakeCase
+	}
+
+	walkAttrs(attrs, "", func(attr loggers.Attr, path string) {
+		if attr.KeyExpr == nil || attr.Key == "" || keyStylePatterns[style].MatchString(attr.Key) {
+			return
+		}
+		if r.conventions != nil {
+			if _, renamed := canonicalKey(r.conventions, path); renamed || slices.Contains(r.conventions.Keys, path) {
+				return
+			}
+		}
+
+		diag := analysis.Diagnostic{
+			Pos:      attr.KeyExpr.Pos(),
+			End:      attr.KeyExpr.End(),
+			Message:  fmt.Sprintf("%s: key %q is not %s", r.Message(), attr.Key, style),
+			Category: r.Name(),
+		}
+
+		if renamed := convertKey(attr.Key, style); renamed != "" {
+			diag.Message += fmt.Sprintf(", use %q", renamed)
+			if edit, ok := renameKeyEdit(attr.KeyExpr, renamed); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   fmt.Sprintf("Rename the key to %q", renamed),
+					TextEdits: []analysis.TextEdit{edit},
+				}}
+			}
+		}
+
+		diagnostics = append(diagnostics, diag)
+	})
+
+	return diagnostics
+}
+
+// convertKey rewrites a key in a style, like user_id for userID in
+// snake_case.
+func convertKey(key string, style KeyStyle) string {
+	switch style {
+	case SnakeCase:
+		return strings.Join(keyWords(key), "_")
+	case CamelCase:
+		words := keyWords(key)
+		for i := 1; i < len(words); i++ {
+			r, size := utf8.DecodeRuneInString(words[i])
+			words[i] = string(unicode.ToUpper(r)) + words[i][size:]
+		}
+		return strings.Join(words, "")
+	case Dotted:
+		var segments []string
+		for _, segment := range strings.Split(key, ".") {
+			if words := keyWords(segment); len(words) > 0 {
+				segments = append(segments, strings.Join(words, "_"))
+			}
+		}
+		return strings.Join(segments, ".")
+	}
+
+	return ""
+}
+
+// keyWords splits a key into lowercase words at separators, camelCase humps
+// and the end of acronyms. Digits stay with the letters before them, so
+// http2Requests gives http2 and requests, and ipV4Addr gives ip, v4 and addr.
+func keyWords(key string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		if i > 0 && len(word) > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			switch {
+			case unicode.IsLower(prev) || unicode.IsDigit(prev):
+				// userID, http2Requests
+				flush()
+			case unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				// HTTPStatus: the last capital starts a new word
+				flush()
+			}
+		}
+
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+// renameKeyEdit replaces a key written as a string literal. Keys declared
+// as constants are left for the author to rename.
+func renameKeyEdit(keyExpr ast.Expr, key string) (analysis.TextEdit, bool) {
+	lit, ok := ast.Unparen(keyExpr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return analysis.TextEdit{}, false
+	}
+
+	return analysis.TextEdit{Pos: lit.Pos(), End: lit.End(), NewText: []byte(strconv.Quote(key))}, true
+}
+
+// walkAttrs calls fn for the attributes a call adds itself, and for those
+// nested in its groups, with the dotted path of their key. Attributes added
+// by With calls on the logger of a call are checked at the With call.
+func walkAttrs(attrs []loggers.Attr, prefix string, fn func(attr loggers.Attr, path string)) {
+	for _, attr := range attrs {
+		if attr.FromWith {
+			continue
+		}
+
+		path := prefix + attr.Key
+		fn(attr, path)
+
+		if len(attr.Group) > 0 && attr.Key != "" {
+			walkAttrs(attr.Group, path+".", fn)
+		}
+	}
+}
+
+// detectWithCalls returns the With calls of a package.
+func detectWithCalls(pass *analysis.Pass) []loggers.WithCall {
+	var withCalls []loggers.WithCall
+
+	detector := loggers.NewDetector(pass)
+	for _, file := range pass.Files {
+		withCalls = append(withCalls, detector.DetectWithCalls(file)...)
+	}
+
+	return withCalls
+}
@@ -0,0 +1,256 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+const keyStyleSrc = `package test
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+)
+
+const userKey = "userID"
+
+var (
+	_ = slog.Info
+	_ = zap.L
+	_ = logrus.Info
+	_ = time.Second
+)
+
+func f(logger *zap.Logger, zl zerolog.Logger, id string, d time.Duration, err error) {
+	BODY
+}
+`
+
+// checkKeys runs a rule over the log calls and With calls of a body.
+func checkKeys(t *testing.T, rule Rule, body string) (*analysis.Pass, string, []analysis.Diagnostic) {
+	t.Helper()
+
+	return checkAttrSource(t, rule, strings.Replace(keyStyleSrc, "BODY", body, 1))
+}
+
+// checkAttrSource runs a rule over the log calls and With calls of a file.
+func checkAttrSource(t *testing.T, rule Rule, src string) (*analysis.Pass, string, []analysis.Diagnostic) {
+	t.Helper()
+
+	pass, file := newTestPass(t, src)
+
+	var diagnostics []analysis.Diagnostic
+	for _, logCall := range loggers.NewDetector(pass).DetectLogCalls(file) {
+		diagnostics = append(diagnostics, rule.Check(pass, logCall)...)
+	}
+	diagnostics = append(diagnostics, rule.(PackageChecker).CheckPackage(pass)...)
+
+	return pass, src, diagnostics
+}
+
+func TestKeyStyleRule(t *testing.T) {
+	tests := []struct {
+		name  string
+		style string
+		body  string
+		want  []string
+	}{
+		{
+			name: "slog",
+			body: `slog.Info("login", "userID", id, "request_id", id)`,
+			want: []string{`key "userID" is not snake_case, use "user_id"`},
+		},
+		{
+			name: "zap",
+			body: `logger.Info("login", zap.String("UserId", id), zap.Error(err))`,
+			want: []string{`key "UserId" is not snake_case, use "user_id"`},
+		},
+		{
+			name: "zerolog",
+			body: `zl.Info().Str("user-id", id).Msg("login")`,
+			want: []string{`key "user-id" is not snake_case, use "user_id"`},
+		},
+		{
+			name: "logrus",
+			body: `logrus.WithFields(logrus.Fields{"userId": id}).WithError(err).Info("login")`,
+			want: []string{`key "userId" is not snake_case, use "user_id"`},
+		},
+		{
+			name: "groups",
+			body: `slog.Info("request", slog.Group("http", "statusCode", 200))`,
+			want: []string{`key "statusCode" is not snake_case`},
+		},
+		{
+			name: "With chain reported once",
+			body: `l := slog.With("requestID", id); l.Info("a"); l.Info("b")`,
+			want: []string{`key "requestID" is not snake_case`},
+		},
+		{
+			name: "digits",
+			body: `slog.Info("request", "http2Requests", 1, "ipV4Addr", id, "HTTPStatus", 200)`,
+			want: []string{
+				`key "http2Requests" is not snake_case, use "http2_requests"`,
+				`key "ipV4Addr" is not snake_case, use "ip_v4_addr"`,
+				`key "HTTPStatus" is not snake_case, use "http_status"`,
+			},
+		},
+		{
+			name:  "camelCase",
+			style: "camelCase",
+			body:  `slog.Info("login", "user_id", id, "userID", id, "requestId", id)`,
+			want:  []string{`key "user_id" is not camelCase, use "userId"`},
+		},
+		{
+			name:  "dotted",
+			style: "dotted",
+			body:  `slog.Info("request", "http.statusCode", 200, "http.method", "GET", "user_id", id)`,
+			want:  []string{`key "http.statusCode" is not dotted, use "http.status_code"`},
+		},
+		{
+			name: "constant keys",
+			body: `slog.Info("login", userKey, id)`,
+			want: []string{`key "userID" is not snake_case`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &KeyStyleRule{}
+			if err := rule.SetStyle(tt.style); err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, diagnostics := checkKeys(t, rule, tt.body)
+
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("Check() reported %d diagnostics, want %d: %v", len(diagnostics), len(tt.want), diagnostics)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(diagnostics[i].Message, want) {
+					t.Errorf("Check() message = %q, want %q", diagnostics[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyStyleRuleFix(t *testing.T) {
+	body := `slog.Info("login", "userID", id, userKey, id); zl.Info().Str("RequestID", id).Msg("login")`
+	want := []string{
+		`slog.Info("login", "user_id", id, userKey, id); zl.Info().Str("RequestID", id).Msg("login")`,
+		`slog.Info("login", "userID", id, userKey, id); zl.Info().Str("request_id", id).Msg("login")`,
+	}
+
+	pass, src, diagnostics := checkKeys(t, &KeyStyleRule{}, body)
+	start := strings.Index(src, body)
+
+	var fixed []string
+	for _, diag := range diagnostics {
+		for _, fix := range diag.SuggestedFixes {
+			text := applyFix(pass, src, fix)
+			fixed = append(fixed, text[start:start+len(body)+len(text)-len(src)])
+		}
+	}
+
+	if !slices.Equal(fixed, want) {
+		t.Errorf("fixed calls = %q, want %q", fixed, want)
+	}
+}
+
+func TestKeyStyleRuleUnknownStyle(t *testing.T) {
+	if err := (&KeyStyleRule{}).SetStyle("kebab-case"); err == nil {
+		t.Error("SetStyle(kebab-case) succeeded, want an error")
+	}
+}
+
+func TestKeySchemaRule(t *testing.T) {
+	schema := map[string]string{
+		"user_id":      "string",
+		"duration_ms":  "int",
+		"timeout":      "duration",
+		"error":        "error",
+		"http.method":  "string",
+		"payload":      "any",
+		"oauth2_token": "string",
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "known keys",
+			body: `slog.Info("done", "user_id", id, slog.Int64("duration_ms", d.Milliseconds()), "timeout", d, "payload", id, slog.Group("http", "method", "GET"))`,
+		},
+		{
+			name: "type mismatch",
+			body: `slog.Info("done", slog.String("duration_ms", d.String()))`,
+			want: []string{`key "duration_ms" takes int values, not string`},
+		},
+		{
+			name: "zap error key",
+			body: `logger.Error("failed", zap.Error(err), zap.Any("timeout", d))`,
+		},
+		{
+			name: "unknown key",
+			body: `zl.Info().Str("session", id).Msg("login")`,
+			want: []string{`key "session" is not in the schema`},
+		},
+		{
+			name: "key differing by style",
+			body: `logrus.WithField("userID", id).Info("login")`,
+			want: []string{`key "userID" is not in the schema, use "user_id"`},
+		},
+		{
+			name: "key with digits differing by style",
+			body: `slog.Info("login", "oauth2Token", id)`,
+			want: []string{`key "oauth2Token" is not in the schema, use "oauth2_token"`},
+		},
+		{
+			name: "group members",
+			body: `slog.Info("request", slog.Group("http", "path", "/", "method", 1))`,
+			want: []string{`key "http.path" is not in the schema`, `key "http.method" takes string values, not int`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &KeySchemaRule{}
+			if err := rule.SetSchema(schema); err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, diagnostics := checkKeys(t, rule, tt.body)
+
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("Check() reported %d diagnostics, want %d: %v", len(diagnostics), len(tt.want), diagnostics)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(diagnostics[i].Message, want) {
+					t.Errorf("Check() message = %q, want %q", diagnostics[i].Message, want)
+				}
+			}
+		})
+	}
+
+	t.Run("no schema", func(t *testing.T) {
+		if _, _, diagnostics := checkKeys(t, &KeySchemaRule{}, `slog.Info("login", "anything", id)`); len(diagnostics) != 0 {
+			t.Errorf("Check() reported %v without a schema", diagnostics)
+		}
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		if err := (&KeySchemaRule{}).SetSchema(map[string]string{"user_id": "uuid"}); err == nil {
+			t.Error("SetSchema() accepted kind uuid")
+		}
+	})
+}
@@ -0,0 +1,265 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+// KeyValueRule checks the key/value pairs of slog calls, of With and
+// slog.Group, and of configured slog wrappers. slog doesn't reject a
+// malformed pair, it logs it under !BADKEY. Pairs must have a constant
+// string key and a value, keys must not repeat within a call or along the
+// With chain of its logger, and slog.Attr values go on their own rather than
+// after a key. go vet's slog check covers neither wrappers nor With chains.
+type KeyValueRule struct {
+	keyStyle KeyStyle
+}
+
+func (r *KeyValueRule) Name() string {
+	return "slog-key-value"
+}
+
+func (r *KeyValueRule) Message() string {
+	return "malformed slog key/value pairs"
+}
+
+// SetKeyStyle sets the style of the keys the fixes infer, snake_case by
+// default.
+func (r *KeyValueRule) SetKeyStyle(style KeyStyle) {
+	r.keyStyle = style
+}
+
+func (r *KeyValueRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	if logCall.Spec.AttrsIndex < 0 || logCall.Logger != loggers.SlogLogger && logCall.Logger != loggers.WrapperLogger {
+		return nil
+	}
+	// LogAttrs takes slog.Attr values, checked by the compiler
+	if logCall.Method == "LogAttrs" {
+		return nil
+	}
+
+	return r.checkAttrs(pass, logCall.Attrs)
+}
+
+// CheckPackage checks the With calls of slog loggers, including those whose
+// logger isn't used to log in the package.
+func (r *KeyValueRule) CheckPackage(pass *analysis.Pass) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	for _, withCall := range detectWithCalls(pass) {
+		if withCall.Logger == loggers.SlogLogger {
+			diagnostics = append(diagnostics, r.checkAttrs(pass, withCall.Attrs)...)
+		}
+	}
+
+	return diagnostics
+}
+
+// checkAttrs checks the attributes of a call. Attributes added by With calls
+// on its logger are checked where they are added, they only count as keys
+// already used.
+func (r *KeyValueRule) checkAttrs(pass *analysis.Pass, attrs []loggers.Attr) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	report := func(node ast.Node, fix *analysis.SuggestedFix, format string, a ...any) {
+		diag := analysis.Diagnostic{
+			Pos:      node.Pos(),
+			End:      node.End(),
+			Message:  fmt.Sprintf("%s: %s", r.Message(), fmt.Sprintf(format, a...)),
+			Category: r.Name(),
+		}
+		if fix != nil {
+			diag.SuggestedFixes = []analysis.SuggestedFix{*fix}
+		}
+		diagnostics = append(diagnostics, diag)
+	}
+
+	attrType := slogAttrType(pass)
+
+	var check func(attrs []loggers.Attr, keys map[string]loggers.Attr)
+	check = func(attrs []loggers.Attr, keys map[string]loggers.Attr) {
+		for i, attr := range attrs {
+			if attr.FromWith {
+				continue
+			}
+
+			if attr.KeyExpr == nil && attr.Func == nil {
+				typ := pass.TypesInfo.TypeOf(attr.Expr)
+				switch {
+				case typ == nil || isSlogAttr(typ):
+				case types.IsInterface(typ) && (types.AssignableTo(types.Typ[types.String], typ) || attrType != nil && types.AssignableTo(attrType, typ)):
+					// the value may be a key or an attribute, the pairs
+					// after it can't be told
+					return
+				default:
+					var fix *analysis.SuggestedFix
+					if wellFormedPairs(pass, attrs[i+1:]) {
+						fix = missingKeyFix(attr.Expr, r.keyStyle)
+					}
+					report(attr.Expr, fix, "%s of type %s is not a key, slog logs it under !BADKEY",
+						types.ExprString(attr.Expr), types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+				}
+				continue
+			}
+
+			if attr.IsPair() {
+				keyType := pass.TypesInfo.TypeOf(attr.KeyExpr)
+				switch {
+				case isNamedType(keyType):
+					report(attr.KeyExpr, &analysis.SuggestedFix{
+						Message: "Convert the key to string",
+						TextEdits: []analysis.TextEdit{
+							{Pos: attr.KeyExpr.Pos(), End: attr.KeyExpr.Pos(), NewText: []byte("string(")},
+							{Pos: attr.KeyExpr.End(), End: attr.KeyExpr.End(), NewText: []byte(")")},
+						},
+					}, "key %s has type %s rather than string, slog logs it under !BADKEY",
+						types.ExprString(attr.KeyExpr), types.TypeString(keyType, types.RelativeTo(pass.Pkg)))
+				case pass.TypesInfo.Types[attr.KeyExpr].Value == nil:
+					report(attr.KeyExpr, nil, "key %s is not a constant", types.ExprString(attr.KeyExpr))
+				}
+
+				if attr.Value == nil {
+					report(attr.KeyExpr, nil, "key %s has no value, slog logs it under !BADKEY", types.ExprString(attr.KeyExpr))
+				} else if isSlogAttr(pass.TypesInfo.TypeOf(attr.Value)) {
+					report(attr.Value, nil, "slog.Attr %s is the value of key %s, pass it on its own or in slog.Group",
+						types.ExprString(attr.Value), types.ExprString(attr.KeyExpr))
+				}
+			}
+
+			if attr.Key != "" {
+				if prev, ok := keys[attr.Key]; ok {
+					where := ""
+					if prev.FromWith {
+						where = " already added by With"
+					}
+					report(attr.Expr, duplicateFix(attrs[:i], prev, attr), "duplicate key %q%s", attr.Key, where)
+				} else {
+					keys[attr.Key] = attr
+				}
+			}
+
+			// groups have keys of their own
+			if len(attr.Group) > 0 {
+				check(attr.Group, make(map[string]loggers.Attr))
+			}
+		}
+	}
+
+	keys := make(map[string]loggers.Attr)
+	for _, attr := range attrs {
+		if _, ok := keys[attr.Key]; attr.FromWith && attr.Key != "" && !ok {
+			keys[attr.Key] = attr
+		}
+	}
+	check(attrs, keys)
+
+	return diagnostics
+}
+
+// wellFormedPairs reports whether attributes are all attributes or keys with
+// a value, so that a key inserted before them doesn't shift the pairs.
+func wellFormedPairs(pass *analysis.Pass, attrs []loggers.Attr) bool {
+	for _, attr := range attrs {
+		switch {
+		case attr.FromWith || attr.Func != nil:
+		case attr.IsPair():
+			if attr.Value == nil {
+				return false
+			}
+		case !isSlogAttr(pass.TypesInfo.TypeOf(attr.Expr)):
+			return false
+		}
+	}
+
+	return true
+}
+
+// missingKeyFix inserts a key named after a value without one, like "user_id"
+// before userID in snake_case. Values without a name have no fix.
+func missingKeyFix(value ast.Expr, style KeyStyle) *analysis.SuggestedFix {
+	name := exprName(value)
+	if name == "" {
+		return nil
+	}
+	if style == "" {
+		style = SnakeCase
+	}
+	key := convertKey(name, style)
+
+	return &analysis.SuggestedFix{
+		Message: fmt.Sprintf("Add the key %q", key),
+		TextEdits: []analysis.TextEdit{
+			{Pos: value.Pos(), End: value.Pos(), NewText: []byte(fmt.Sprintf("%q, ", key))},
+		},
+	}
+}
+
+// duplicateFix removes a pair repeating an earlier pair of the same call
+// word for word. Pairs with different values have no fix, as only the
+// author knows which one to keep.
+func duplicateFix(before []loggers.Attr, prev, attr loggers.Attr) *analysis.SuggestedFix {
+	if prev.FromWith || len(before) == 0 || !prev.IsPair() || !attr.IsPair() || prev.Value == nil || attr.Value == nil {
+		return nil
+	}
+	if types.ExprString(prev.Value) != types.ExprString(attr.Value) {
+		return nil
+	}
+
+	return &analysis.SuggestedFix{
+		Message: fmt.Sprintf("Remove the repeated %q pair", attr.Key),
+		TextEdits: []analysis.TextEdit{
+			{Pos: attrEnd(before[len(before)-1]), End: attrEnd(attr)},
+		},
+	}
+}
+
+// attrEnd returns the end of the last expression of an attribute.
+func attrEnd(attr loggers.Attr) token.Pos {
+	if attr.IsPair() && attr.Value != nil {
+		return attr.Value.End()
+	}
+
+	return attr.Expr.End()
+}
+
+func isSlogAttr(typ types.Type) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	return ok && qualifiedName(named) == "log/slog.Attr"
+}
+
+// isNamedType reports whether typ is a defined type, like a string type
+// declared for keys.
+func isNamedType(typ types.Type) bool {
+	_, ok := types.Unalias(typ).(*types.Named)
+	return ok
+}
+
+// slogAttrType returns slog.Attr if the package imports log/slog, directly
+// or not.
+func slogAttrType(pass *analysis.Pass) types.Type {
+	seen := make(map[*types.Package]bool)
+
+	var find func(pkgs []*types.Package) types.Type
+	find = func(pkgs []*types.Package) types.Type {
+		for _, pkg := range pkgs {
+			if seen[pkg] {
+				continue
+			}
+			seen[pkg] = true
+			if pkg.Path() == "log/slog" {
+				return pkg.Scope().Lookup("Attr").Type()
+			}
+			if typ := find(pkg.Imports()); typ != nil {
+				return typ
+			}
+		}
+		return nil
+	}
+
+	return find(pass.Pkg.Imports())
+}
@@ -0,0 +1,205 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+const keyValueSrc = `package test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+type key string
+
+const keyUser key = "user"
+
+type Logger struct{}
+
+func (l *Logger) Info(msg string, args ...any) {}
+
+var (
+	_ = context.Background
+	_ = fmt.Sprint
+)
+
+func f(ctx context.Context, l *Logger, logger *slog.Logger, userID int, name string, v any, s fmt.Stringer, err error) {
+	BODY
+}
+`
+
+// checkKeyValues runs the slog-key-value rule over the log calls and With
+// calls of a body, with test.Logger.Info as a slog wrapper.
+func checkKeyValues(t *testing.T, body string) (*analysis.Pass, string, []analysis.Diagnostic) {
+	t.Helper()
+
+	src := strings.Replace(keyValueSrc, "BODY", body, 1)
+	pass, file := newTestPass(t, src)
+
+	detector := loggers.NewDetector(pass)
+	detector.SetSlogWrappers([]string{"(*test.Logger).Info"})
+
+	rule := &KeyValueRule{}
+	var diagnostics []analysis.Diagnostic
+	for _, logCall := range detector.DetectLogCalls(file) {
+		diagnostics = append(diagnostics, rule.Check(pass, logCall)...)
+	}
+	diagnostics = append(diagnostics, rule.CheckPackage(pass)...)
+
+	return pass, src, diagnostics
+}
+
+func TestKeyValueRule(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "valid pairs",
+			body: `slog.Info("login", "user", name, slog.Int("id", userID), slog.Group("req", "method", "GET"))`,
+		},
+		{
+			name: "odd number of arguments",
+			body: `slog.Info("login", "user", name, "id")`,
+			want: []string{`key "id" has no value`},
+		},
+		{
+			name: "value without key",
+			body: `slog.Error("failed", err)`,
+			want: []string{"err of type error is not a key"},
+		},
+		{
+			name: "named key type",
+			body: `slog.Info("login", keyUser, name)`,
+			want: []string{"key keyUser has type key rather than string"},
+		},
+		{
+			name: "non-constant key",
+			body: `slog.Info("login", name, userID)`,
+			want: []string{"key name is not a constant"},
+		},
+		{
+			name: "attribute as value",
+			body: `slog.Info("login", "user", slog.String("name", name))`,
+			want: []string{`slog.Attr slog.String("name", name) is the value of key "user"`},
+		},
+		{
+			name: "duplicate keys",
+			body: `slog.Info("login", "user", name, slog.Int("user", userID))`,
+			want: []string{`duplicate key "user"`},
+		},
+		{
+			name: "duplicate key of the With chain",
+			body: `reqLogger := logger.With("user", name); reqLogger.With("id", userID).InfoContext(ctx, "login", "user", name)`,
+			want: []string{`duplicate key "user" already added by With`},
+		},
+		{
+			name: "duplicate key within With",
+			body: `slog.With("user", name).With("user", userID)`,
+			want: []string{`duplicate key "user" already added by With`},
+		},
+		{
+			name: "unused With",
+			body: `_ = logger.With("user")`,
+			want: []string{`key "user" has no value`},
+		},
+		{
+			name: "groups have their own keys",
+			body: `slog.Info("login", "id", userID, slog.Group("user", "id", userID, "name"))`,
+			want: []string{`key "name" has no value`},
+		},
+		{
+			name: "wrapper",
+			body: `l.Info("login", "user", name, userID)`,
+			want: []string{"userID of type int is not a key"},
+		},
+		{
+			name: "unknown dynamic type",
+			body: `slog.Info("login", v, 1, 2); slog.Info("login", s, 1)`,
+		},
+		{
+			name: "attributes are typed",
+			body: `logger.LogAttrs(ctx, slog.LevelInfo, "login", slog.String("user", name), slog.String("user", name))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, diagnostics := checkKeyValues(t, tt.body)
+
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("Check() reported %d diagnostics, want %d: %v", len(diagnostics), len(tt.want), diagnostics)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(diagnostics[i].Message, want) {
+					t.Errorf("Check() message = %q, want %q", diagnostics[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyValueRuleFix(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "missing key",
+			body: `slog.Info("login", userID)`,
+			want: []string{`slog.Info("login", "user_id", userID)`},
+		},
+		{
+			name: "missing key before pairs",
+			body: `slog.Info("login", userID, "user", name, slog.Int("n", 1))`,
+			want: []string{`slog.Info("login", "user_id", userID, "user", name, slog.Int("n", 1))`},
+		},
+		{
+			name: "missing key before a dangling value",
+			body: `slog.Info("login", userID, name)`,
+		},
+		{
+			name: "named key type",
+			body: `slog.Info("login", keyUser, name)`,
+			want: []string{`slog.Info("login", string(keyUser), name)`},
+		},
+		{
+			name: "repeated pair",
+			body: `slog.Info("login", "user", name, "id", userID, "user", name)`,
+			want: []string{`slog.Info("login", "user", name, "id", userID)`},
+		},
+		{
+			name: "ambiguous",
+			body: `slog.Info("login", "user", name, "user", userID, 42)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pass, src, diagnostics := checkKeyValues(t, tt.body)
+			start := strings.Index(src, tt.body)
+			end := start + len(tt.body)
+
+			var fixed []string
+			for _, diag := range diagnostics {
+				for _, fix := range diag.SuggestedFixes {
+					text := applyFix(pass, src, fix)
+					fixed = append(fixed, text[start:end+len(text)-len(src)])
+				}
+			}
+
+			if !slices.Equal(fixed, tt.want) {
+				t.Errorf("fixed calls = %q, want %q", fixed, tt.want)
+			}
+		})
+	}
+}
@@ -12,53 +12,50 @@ import (
 type LowercaseRule struct{}
 
 func (r *LowercaseRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
-	message, ok := loggers.ExtractStringLit(logCall.Message)
-	if !ok {
+	first, exact, ok := r.firstChar(logCall.Text)
+	if !ok || !unicode.IsUpper(first.Value) {
 		return nil
 	}
 
-	if len(message) == 0 {
-		return nil
-	}
-
-	firstCharIndex := 0
-	for firstCharIndex < len(message) && unicode.IsSpace(rune(message[firstCharIndex])) {
-		firstCharIndex++
+	diag := analysis.Diagnostic{
+		Pos:      first.Pos,
+		End:      first.End,
+		Message:  r.Message(),
+		Category: r.Name(),
 	}
 
-	if firstCharIndex >= len(message) {
-		return nil
+	if exact {
+		diag.SuggestedFixes = []analysis.SuggestedFix{
+			{
+				Message: "Convert first letter to lowercase",
+				TextEdits: []analysis.TextEdit{{
+					Pos:     first.Pos,
+					End:     first.End,
+					NewText: []byte(string(unicode.ToLower(first.Value))),
+				}},
+			},
+		}
 	}
 
-	firstChar := rune(message[firstCharIndex])
+	return []analysis.Diagnostic{diag}
+}
 
-	if !unicode.IsLetter(firstChar) {
-		return nil
+// firstChar returns the first non-space character of the message, if the
+// message starts with constant text, and whether it can be rewritten in place.
+func (r *LowercaseRule) firstChar(message loggers.Message) (loggers.Rune, bool, bool) {
+	for _, seg := range message.Segments {
+		if seg.Kind != loggers.LiteralSegment {
+			break
+		}
+
+		for _, char := range seg.Runes {
+			if !unicode.IsSpace(char.Value) {
+				return char, seg.Exact, true
+			}
+		}
 	}
 
-	if unicode.IsUpper(firstChar) {
-		suggested := string(unicode.ToLower(firstChar)) + message[firstCharIndex+1:]
-		logCallPos := logCall.Call.Pos()
-		logCallEnd := logCall.Call.End()
-		diag := []analysis.Diagnostic{{
-			Pos:      logCallPos,
-			End:      logCallEnd,
-			Message:  r.Message(),
-			Category: r.Name(),
-			SuggestedFixes: []analysis.SuggestedFix{
-				{
-					Message: "Convert first letter to lowercase",
-					TextEdits: []analysis.TextEdit{{
-						Pos:     logCallPos,
-						End:     logCallEnd,
-						NewText: []byte(fmt.Sprintf(`"%s"`, suggested)),
-					}},
-				},
-			},
-		}}
-		return diag
-	}
-	return nil
+	return loggers.Rune{}, false, false
 }
 
 func (r *LowercaseRule) Name() string {
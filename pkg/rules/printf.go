@@ -0,0 +1,529 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// printfArgs is a set of kinds of arguments a printf verb accepts.
+type printfArgs int
+
+const (
+	argBool printfArgs = 1 << iota
+	argInt
+	argRune
+	argString
+	argFloat
+	argComplex
+	argPointer
+
+	anyArg printfArgs = -1
+)
+
+// printfVerbs are the verbs of fmt and the arguments they format.
+var printfVerbs = map[rune]printfArgs{
+	'b': argInt | argFloat | argComplex | argPointer,
+	'c': argRune | argInt,
+	'd': argInt | argPointer,
+	'e': argFloat | argComplex,
+	'E': argFloat | argComplex,
+	'f': argFloat | argComplex,
+	'F': argFloat | argComplex,
+	'g': argFloat | argComplex,
+	'G': argFloat | argComplex,
+	'o': argInt | argPointer,
+	'O': argInt | argPointer,
+	'p': argPointer,
+	'q': argRune | argInt | argString,
+	's': argString,
+	't': argBool,
+	'T': anyArg,
+	'U': argRune | argInt,
+	'v': anyArg,
+	'x': argRune | argInt | argString | argPointer | argFloat | argComplex,
+	'X': argRune | argInt | argString | argPointer | argFloat | argComplex,
+}
+
+// printfVerb is a directive of a format string, like %-8s.
+type printfVerb struct {
+	verb rune
+	// directive as written, and its offset in the format
+	text string
+	pos  int
+	// index of the formatted argument, -1 for %%
+	arg int
+	// indexes of the arguments * widths and precisions take
+	stars []int
+	// an argument index like [2] is used
+	indexed bool
+}
+
+// PrintfRule checks the formats of printf-style log methods: the format
+// must be constant, and its verbs must match the number and types of the
+// arguments. go vet's printf check doesn't know zap's ...f methods or our
+// wrappers; for the log package it only reports non-constant formats, as vet
+// already checks its verbs.
+type PrintfRule struct{}
+
+func (r *PrintfRule) Name() string {
+	return "printf-format"
+}
+
+func (r *PrintfRule) Message() string {
+	return "invalid printf format"
+}
+
+func (r *PrintfRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	if !logCall.Format {
+		return nil
+	}
+
+	tv, ok := pass.TypesInfo.Types[logCall.Message]
+	if !ok {
+		return nil
+	}
+
+	if tv.Value == nil {
+		// a wrapper forwarding its format and arguments is checked at its
+		// callers
+		if len(logCall.FormatArgs) > 0 && formatParam(pass, logCall) {
+			return nil
+		}
+		return []analysis.Diagnostic{r.nonConstant(pass, logCall)}
+	}
+
+	// arguments spread from a slice can't be counted
+	if tv.Value.Kind() != constant.String || logCall.Logger == loggers.LogLogger || logCall.Call.Ellipsis.IsValid() {
+		return nil
+	}
+
+	return r.checkVerbs(pass, logCall, constant.StringVal(tv.Value))
+}
+
+// nonConstant reports a format that isn't constant, suggesting the
+// print-style method or a %s format if it has no arguments.
+func (r *PrintfRule) nonConstant(pass *analysis.Pass, logCall loggers.LogCall) analysis.Diagnostic {
+	callName := types.ExprString(logCall.Call.Fun)
+	msg := logCall.Message
+
+	diag := analysis.Diagnostic{
+		Pos:      msg.Pos(),
+		End:      msg.End(),
+		Message:  fmt.Sprintf("%s: non-constant format string in call to %s", r.Message(), callName),
+		Category: r.Name(),
+	}
+
+	if len(logCall.FormatArgs) > 0 || logCall.Call.Ellipsis.IsValid() {
+		return diag
+	}
+
+	if ident, name, ok := printVariant(pass, logCall); ok {
+		diag.SuggestedFixes = append(diag.SuggestedFixes, analysis.SuggestedFix{
+			Message: fmt.Sprintf("Use %s instead of %s", name, logCall.Method),
+			TextEdits: []analysis.TextEdit{
+				{Pos: ident.Pos(), End: ident.End(), NewText: []byte(name)},
+			},
+		})
+	}
+
+	diag.SuggestedFixes = append(diag.SuggestedFixes, analysis.SuggestedFix{
+		Message: `Insert a "%s" format`,
+		TextEdits: []analysis.TextEdit{
+			{Pos: msg.Pos(), End: msg.Pos(), NewText: []byte(`"%s", `)},
+		},
+	})
+
+	return diag
+}
+
+// formatParam reports whether the format of a call is a parameter of a
+// function enclosing it, like the format of a Debugf wrapper calling
+// log.Printf(format, args...).
+func formatParam(pass *analysis.Pass, logCall loggers.LogCall) bool {
+	ident, ok := ast.Unparen(logCall.Message).(*ast.Ident)
+	if !ok {
+		return false
+	}
+	obj, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok {
+		return false
+	}
+
+	file := fileOf(pass, logCall.Call.Pos())
+	if file == nil {
+		return false
+	}
+
+	path, _ := astutil.PathEnclosingInterval(file, logCall.Call.Pos(), logCall.Call.End())
+	for _, node := range path {
+		var typ *ast.FuncType
+		switch n := node.(type) {
+		case *ast.FuncDecl:
+			typ = n.Type
+		case *ast.FuncLit:
+			typ = n.Type
+		default:
+			continue
+		}
+
+		for _, field := range typ.Params.List {
+			for _, name := range field.Names {
+				if pass.TypesInfo.Defs[name] == obj {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// printVariant returns the method name of a call and the name of the
+// print-style method of the same logger it can be replaced with, like Info
+// for Infof. Calls through function values have none.
+func printVariant(pass *analysis.Pass, logCall loggers.LogCall) (*ast.Ident, string, bool) {
+	name, ok := strings.CutSuffix(logCall.Method, "f")
+	if !ok {
+		return nil, "", false
+	}
+
+	ident, spec, ok := methodVariant(pass, logCall, name)
+	if !ok || spec.Printf {
+		return nil, "", false
+	}
+
+	return ident, name, true
+}
+
+// methodVariant returns the method name of a call and the spec of the
+// logging method name of the same logger, if it takes its message at the
+// same index.
+func methodVariant(pass *analysis.Pass, logCall loggers.LogCall, name string) (*ast.Ident, loggers.MethodSpec, bool) {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(logCall.Call.Fun).(type) {
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	case *ast.Ident:
+		ident = fun
+	}
+	if ident == nil || ident.Name != logCall.Method {
+		return nil, loggers.MethodSpec{}, false
+	}
+
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return nil, loggers.MethodSpec{}, false
+	}
+
+	var variant types.Object
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		variant, _, _ = types.LookupFieldOrMethod(recv.Type(), true, fn.Pkg(), name)
+	} else {
+		variant = fn.Pkg().Scope().Lookup(name)
+	}
+
+	variantFn, ok := variant.(*types.Func)
+	if !ok {
+		return nil, loggers.MethodSpec{}, false
+	}
+
+	spec, ok := loggers.LookupMethod(loggers.LoggerTypeOf(variantFn), name)
+	if !ok || spec.MessageIndex != logCall.Spec.MessageIndex-methodExprShift(pass, logCall) {
+		return nil, loggers.MethodSpec{}, false
+	}
+
+	return ident, spec, true
+}
+
+// methodExprShift returns 1 for calls of method expressions like
+// (*zap.SugaredLogger).Infof, which take the receiver first.
+func methodExprShift(pass *analysis.Pass, logCall loggers.LogCall) int {
+	if sel, ok := ast.Unparen(logCall.Call.Fun).(*ast.SelectorExpr); ok {
+		if selection, ok := pass.TypesInfo.Selections[sel]; ok && selection.Kind() == types.MethodExpr {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// checkVerbs reports verbs of a constant format that don't match the
+// arguments of the call.
+func (r *PrintfRule) checkVerbs(pass *analysis.Pass, logCall loggers.LogCall, format string) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	callName := types.ExprString(logCall.Call.Fun)
+	args := logCall.FormatArgs
+	msg := logCall.Message
+
+	report := func(node ast.Node, format string, a ...any) {
+		diagnostics = append(diagnostics, analysis.Diagnostic{
+			Pos:      node.Pos(),
+			End:      node.End(),
+			Message:  fmt.Sprintf("%s: %s %s", r.Message(), callName, fmt.Sprintf(format, a...)),
+			Category: r.Name(),
+		})
+	}
+
+	verbs, err := parsePrintf(format)
+	if err != nil {
+		report(msg, "format %v", err)
+		return diagnostics
+	}
+
+	qualifier := types.RelativeTo(pass.Pkg)
+	used := 0
+	indexed := false
+
+	for _, v := range verbs {
+		indexed = indexed || v.indexed
+
+		for _, star := range v.stars {
+			used = max(used, star+1)
+			if star >= len(args) {
+				report(msg, "format %s reads arg #%d, but call has %s", v.text, star+1, pluralArgs(len(args)))
+				return diagnostics
+			}
+			if typ := pass.TypesInfo.TypeOf(args[star]); typ != nil && !isInt(typ) {
+				report(args[star], "format %s uses non-int %s as argument of *", v.text, types.ExprString(args[star]))
+			}
+		}
+
+		if v.verb == '%' {
+			continue
+		}
+		used = max(used, v.arg+1)
+
+		if v.verb == 'w' {
+			report(msg, "does not support error-wrapping directive %s", v.text)
+			continue
+		}
+
+		want, ok := printfVerbs[v.verb]
+		if !ok {
+			report(msg, "format %s has unknown verb %c", v.text, v.verb)
+			continue
+		}
+
+		if v.arg >= len(args) {
+			report(msg, "format %s reads arg #%d, but call has %s", v.text, v.arg+1, pluralArgs(len(args)))
+			return diagnostics
+		}
+
+		arg := args[v.arg]
+		typ := pass.TypesInfo.TypeOf(arg)
+		if typ != nil && !matchArg(typ, want, make(map[types.Type]bool)) {
+			report(arg, "format %s has arg %s of wrong type %s", v.text, types.ExprString(arg), types.TypeString(typ, qualifier))
+		}
+	}
+
+	// explicit indexes may leave arguments out on purpose
+	if !indexed && used < len(args) {
+		report(args[used], "call needs %s but has %s", pluralArgs(used), pluralArgs(len(args)))
+	}
+
+	return diagnostics
+}
+
+func pluralArgs(n int) string {
+	if n == 1 {
+		return "1 arg"
+	}
+	return fmt.Sprintf("%d args", n)
+}
+
+// parsePrintf splits a format into its directives.
+func parsePrintf(format string) ([]printfVerb, error) {
+	var verbs []printfVerb
+	arg := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		start := i
+		v := printfVerb{}
+		i++
+
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+
+		// index reads an argument index like [2]
+		index := func() error {
+			if i >= len(format) || format[i] != '[' {
+				return nil
+			}
+			end := strings.IndexByte(format[i:], ']')
+			if end < 0 {
+				return fmt.Errorf("%s has an unterminated argument index", format[start:])
+			}
+			n, err := strconv.Atoi(format[i+1 : i+end])
+			if err != nil || n < 1 {
+				return fmt.Errorf("%s has an invalid argument index", format[start:i+end+1])
+			}
+			arg = n - 1
+			v.indexed = true
+			i += end + 1
+			return nil
+		}
+
+		// number reads a width or precision, a * takes an argument
+		number := func() error {
+			if err := index(); err != nil {
+				return err
+			}
+			if i < len(format) && format[i] == '*' {
+				v.stars = append(v.stars, arg)
+				arg++
+				i++
+				return nil
+			}
+			for i < len(format) && '0' <= format[i] && format[i] <= '9' {
+				i++
+			}
+			return nil
+		}
+
+		if err := number(); err != nil {
+			return nil, err
+		}
+		if i < len(format) && format[i] == '.' {
+			i++
+			if err := number(); err != nil {
+				return nil, err
+			}
+		}
+		if err := index(); err != nil {
+			return nil, err
+		}
+
+		if i >= len(format) {
+			return nil, fmt.Errorf("%s is missing a verb", format[start:])
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		v.verb = verb
+		v.text = format[start : i+1]
+		v.pos = start
+		v.arg = -1
+		if verb != '%' {
+			v.arg = arg
+			arg++
+		}
+
+		verbs = append(verbs, v)
+	}
+
+	return verbs, nil
+}
+
+// matchArg reports whether a verb accepting want can format a value of typ.
+// Values formatting themselves, and interfaces, whose dynamic type isn't
+// known, match any verb.
+func matchArg(typ types.Type, want printfArgs, seen map[types.Type]bool) bool {
+	if want == anyArg || seen[typ] || types.IsInterface(typ) || hasMethod(typ, "Format", 2, 0) {
+		return true
+	}
+	seen[typ] = true
+
+	if want&argString != 0 && (hasMethod(typ, "String", 0, 1) || hasMethod(typ, "Error", 0, 1)) {
+		return true
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		info := t.Info()
+		switch {
+		case t.Kind() == types.UnsafePointer:
+			return want&argPointer != 0
+		case info&types.IsBoolean != 0:
+			return want&argBool != 0
+		case info&types.IsInteger != 0:
+			return want&(argInt|argRune) != 0
+		case info&types.IsFloat != 0:
+			return want&argFloat != 0
+		case info&types.IsComplex != 0:
+			return want&argComplex != 0
+		case info&types.IsString != 0:
+			return want&argString != 0
+		}
+		return false
+
+	case *types.Pointer:
+		if want&argPointer != 0 {
+			return true
+		}
+		// pointers to composites are printed like &{...}
+		switch t.Elem().Underlying().(type) {
+		case *types.Struct, *types.Array, *types.Slice, *types.Map:
+			return matchArg(t.Elem(), want, seen)
+		}
+		return false
+
+	case *types.Slice:
+		if want&argPointer != 0 || (want&argString != 0 && isByte(t.Elem())) {
+			return true
+		}
+		return matchArg(t.Elem(), want, seen)
+
+	case *types.Array:
+		if want&argString != 0 && isByte(t.Elem()) {
+			return true
+		}
+		return matchArg(t.Elem(), want, seen)
+
+	case *types.Map:
+		if want&argPointer != 0 {
+			return true
+		}
+		return matchArg(t.Key(), want, seen) && matchArg(t.Elem(), want, seen)
+
+	case *types.Struct:
+		for field := range t.Fields() {
+			if !matchArg(field.Type(), want, seen) {
+				return false
+			}
+		}
+		return true
+
+	case *types.Chan, *types.Signature:
+		return want&argPointer != 0
+	}
+
+	return false
+}
+
+// hasMethod reports whether typ has a method with the given numbers of
+// parameters and results.
+func hasMethod(typ types.Type, name string, params, results int) bool {
+	obj, _, _ := types.LookupFieldOrMethod(typ, false, nil, name)
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == params && sig.Results().Len() == results
+}
+
+func isInt(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsInteger != 0
+}
+
+func isByte(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && (basic.Kind() == types.Byte || basic.Kind() == types.Uint8)
+}
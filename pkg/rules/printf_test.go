@@ -0,0 +1,239 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+)
+
+func TestPrintfRule(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "non-constant log format",
+			body: `log.Printf(msg)`,
+			want: []string{"non-constant format string in call to log.Printf"},
+		},
+		{
+			name: "non-constant sugar format",
+			body: `sugar.Infof("user " + msg)`,
+			want: []string{"non-constant format string in call to sugar.Infof"},
+		},
+		{
+			name: "forwarded format",
+			body: `log.Printf(msg, n)`,
+		},
+		{
+			name: "forwarded from a closure",
+			body: `func() { sugar.Infof(msg, n) }()`,
+		},
+		{
+			name: "untrusted format with arguments",
+			body: `sugar.Infof(r.FormValue("f"), n)`,
+			want: []string{"non-constant format string in call to sugar.Infof"},
+		},
+		{
+			name: "local format with arguments",
+			body: `format := "user " + msg; log.Printf(format, n)`,
+			want: []string{"non-constant format string in call to log.Printf"},
+		},
+		{
+			name: "log verbs are left to vet",
+			body: `log.Printf("%d", msg)`,
+		},
+		{
+			name: "wrong type",
+			body: `sugar.Infof("user %d logged in", msg)`,
+			want: []string{"sugar.Infof format %d has arg msg of wrong type string"},
+		},
+		{
+			name: "missing argument",
+			body: `sugar.Infof("user %s has %d items", msg)`,
+			want: []string{"sugar.Infof format %d reads arg #2, but call has 1 arg"},
+		},
+		{
+			name: "extra argument",
+			body: `sugar.Infof("user %s", msg, n)`,
+			want: []string{"sugar.Infof call needs 1 arg but has 2 args"},
+		},
+		{
+			name: "unknown verb",
+			body: `sugar.Infof("user %z", msg)`,
+			want: []string{"sugar.Infof format %z has unknown verb z"},
+		},
+		{
+			name: "error wrapping",
+			body: `sugar.Infof("failed: %w", err)`,
+			want: []string{"sugar.Infof does not support error-wrapping directive %w"},
+		},
+		{
+			name: "star width",
+			body: `sugar.Infof("%*s|", msg, msg)`,
+			want: []string{"sugar.Infof format %*s uses non-int msg as argument of *"},
+		},
+		{
+			name: "valid verbs",
+			body: `sugar.Infof("%-8s %5.2f %x %v %q %t %p %d%% %[1]s", msg, 1.5, []byte(msg), u, 'a', true, &n, n)`,
+		},
+		{
+			name: "stringer and error",
+			body: `sugar.Infof("%s %s %d", id, err, id)`,
+		},
+		{
+			name: "struct fields",
+			body: `sugar.Infof("%d", u)`,
+			want: []string{"sugar.Infof format %d has arg u of wrong type user"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := `package test
+
+import (
+	"log"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+type user struct {
+	Name string
+	Age  int
+}
+
+type userID int
+
+func (id userID) String() string { return "" }
+
+var _ = log.Print
+
+func f(sugar *zap.SugaredLogger, msg string, n int, u user, id userID, err error, r *http.Request) {
+	` + tt.body + `
+}
+`
+			diagnostics := checkSource(t, &PrintfRule{}, src)
+
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("Check() reported %d diagnostics, want %d: %v", len(diagnostics), len(tt.want), diagnostics)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(diagnostics[i].Message, want) {
+					t.Errorf("Check() message = %q, want %q", diagnostics[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPrintfRuleFix(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "log",
+			body: `log.Printf(msg)`,
+			want: []string{"log.Print(msg)", `log.Printf("%s", msg)`},
+		},
+		{
+			name: "sugar",
+			body: `sugar.Infof(msg)`,
+			want: []string{"sugar.Info(msg)", `sugar.Infof("%s", msg)`},
+		},
+		{
+			name: "function value",
+			body: `logf := log.Printf; logf(msg)`,
+			want: []string{`logf("%s", msg)`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := `package test
+
+import (
+	"log"
+
+	"go.uber.org/zap"
+)
+
+var _ = log.Print
+
+func f(sugar *zap.SugaredLogger, msg string) {
+	` + tt.body + `
+}
+`
+			pass, file := newTestPass(t, src)
+
+			var fixed []string
+			for _, logCall := range loggers.NewDetector(pass).DetectLogCalls(file) {
+				for _, diag := range (&PrintfRule{}).Check(pass, logCall) {
+					call := logCall.Call
+					start := pass.Fset.Position(call.Pos()).Offset
+					end := pass.Fset.Position(call.End()).Offset
+
+					for _, fix := range diag.SuggestedFixes {
+						text := applyFix(pass, src, fix)
+						fixed = append(fixed, text[start:end+len(text)-len(src)])
+					}
+				}
+			}
+
+			if !slices.Equal(fixed, tt.want) {
+				t.Errorf("fixed calls = %q, want %q", fixed, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintfRuleWrappers(t *testing.T) {
+	src := `package test
+
+import "fmt"
+
+type Logger struct{}
+
+func (l *Logger) Debugf(format string, args ...any) {}
+
+func Errorf(format string, args ...any) {}
+
+var _ = fmt.Sprint
+
+func f(l *Logger, msg string) {
+	l.Debugf(msg)
+	Errorf("failed after %d tries", msg)
+	Errorf("failed: %v", fmt.Errorf("%s", msg))
+}
+`
+	want := []string{
+		"non-constant format string in call to l.Debugf",
+		"Errorf format %d has arg msg of wrong type string",
+	}
+
+	pass, file := newTestPass(t, src)
+	detector := loggers.NewDetector(pass)
+	detector.SetPrintfWrappers([]string{"(*test.Logger).Debugf", "test.Errorf"})
+
+	var messages []string
+	for _, logCall := range detector.DetectLogCalls(file) {
+		for _, diag := range (&PrintfRule{}).Check(pass, logCall) {
+			messages = append(messages, diag.Message)
+		}
+	}
+
+	if len(messages) != len(want) {
+		t.Fatalf("Check() reported %q, want %d diagnostics", messages, len(want))
+	}
+	for i := range want {
+		if !strings.Contains(messages[i], want[i]) {
+			t.Errorf("Check() message = %q, want %q", messages[i], want[i])
+		}
+	}
+}
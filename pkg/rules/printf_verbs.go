@@ -0,0 +1,150 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+// attrTypes are the types of attributes passed to structured log methods,
+// which are never the arguments of a misplaced verb.
+var attrTypes = []string{"log/slog.Attr", "go.uber.org/zap.Field", "go.uber.org/zap/zapcore.Field"}
+
+// PrintfVerbsRule reports printf verbs in the messages of methods that don't
+// format them, like slog.Info("processed %d items", n): the verb is logged
+// literally, and the argument becomes a broken key/value pair. It suggests
+// moving the arguments into attributes, or the printf-style method of the
+// logger if it has one, like Infof for sugar.Info.
+type PrintfVerbsRule struct {
+	keyStyle KeyStyle
+}
+
+func (r *PrintfVerbsRule) Name() string {
+	return "no-printf-verbs"
+}
+
+func (r *PrintfVerbsRule) Message() string {
+	return "printf verb in a message that isn't formatted"
+}
+
+// SetKeyStyle sets the style of the keys the fix infers, snake_case by
+// default.
+func (r *PrintfVerbsRule) SetKeyStyle(style KeyStyle) {
+	r.keyStyle = style
+}
+
+func (r *PrintfVerbsRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	if logCall.Format {
+		return nil
+	}
+
+	format, ok := constString(pass, logCall.Message)
+	if !ok {
+		return nil
+	}
+
+	verbs := messageVerbs(format)
+	if len(verbs) == 0 {
+		return nil
+	}
+
+	callName := types.ExprString(logCall.Call.Fun)
+	msg := logCall.Message
+	diag := analysis.Diagnostic{
+		Pos:      msg.Pos(),
+		End:      msg.End(),
+		Message:  fmt.Sprintf("%s: %s doesn't format its message, %s is logged literally", r.Message(), callName, verbs[0].text),
+		Category: r.Name(),
+	}
+
+	// the arguments after the message meant for the verbs
+	args := logCall.Call.Args[logCall.Spec.MessageIndex+1:]
+	if len(args) < len(verbs) || logCall.Call.Ellipsis.IsValid() || slices.ContainsFunc(args[:len(verbs)], func(arg ast.Expr) bool {
+		return slices.Contains(attrTypes, qualifiedName(pass.TypesInfo.TypeOf(arg)))
+	}) {
+		return []analysis.Diagnostic{diag}
+	}
+	rest := args[len(verbs):]
+	args = args[:len(verbs)]
+
+	if wellFormedRest(pass, logCall, args, rest) {
+		if edits, ok := templateEdits(pass, logCall, format, args, args[len(args)-1].End(), r.keyStyle); ok {
+			diag.SuggestedFixes = append(diag.SuggestedFixes, analysis.SuggestedFix{
+				Message:   "Move the arguments of the verbs into attributes",
+				TextEdits: edits,
+			})
+		}
+	}
+
+	// the printf-style method would take the other arguments as format
+	// arguments too
+	if len(args) == len(logCall.Call.Args)-logCall.Spec.MessageIndex-1 {
+		if ident, spec, ok := methodVariant(pass, logCall, logCall.Method+"f"); ok && spec.Printf {
+			diag.SuggestedFixes = append(diag.SuggestedFixes, analysis.SuggestedFix{
+				Message: fmt.Sprintf("Use %sf instead of %s", logCall.Method, logCall.Method),
+				TextEdits: []analysis.TextEdit{
+					{Pos: ident.Pos(), End: ident.End(), NewText: []byte(logCall.Method + "f")},
+				},
+			})
+		}
+	}
+
+	return []analysis.Diagnostic{diag}
+}
+
+// wellFormedRest reports whether the arguments of a call are still
+// attributes once the arguments of the verbs move: the rest of the
+// arguments must be whole pairs of a constant key and a value, or attribute
+// values, and none of the verbs' arguments may read as the key of a pair.
+// Print-style methods take no other arguments.
+func wellFormedRest(pass *analysis.Pass, logCall loggers.LogCall, args, rest []ast.Expr) bool {
+	if logCall.Spec.AttrsIndex < 0 {
+		return len(rest) == 0
+	}
+
+	for i, arg := range args {
+		if _, ok := constString(pass, arg); ok && (i < len(args)-1 || len(rest) > 0) {
+			return false
+		}
+	}
+
+	for i := 0; i < len(rest); i++ {
+		if slices.Contains(attrTypes, qualifiedName(pass.TypesInfo.TypeOf(rest[i]))) {
+			continue
+		}
+		if _, ok := constString(pass, rest[i]); !ok || i+1 == len(rest) {
+			return false
+		}
+		i++
+	}
+
+	return true
+}
+
+// messageVerbs returns the verbs of a message taking an argument. Messages
+// that aren't valid formats have none, and verbs with a space flag, like the
+// "% d" of "50% done", are taken for literal percent signs.
+func messageVerbs(message string) []printfVerb {
+	verbs, err := parsePrintf(message)
+	if err != nil {
+		return nil
+	}
+
+	var taking []printfVerb
+	for _, v := range verbs {
+		if v.verb == '%' {
+			continue
+		}
+		if _, ok := printfVerbs[v.verb]; !ok || strings.Contains(v.text, " ") {
+			return nil
+		}
+		taking = append(taking, v)
+	}
+
+	return taking
+}
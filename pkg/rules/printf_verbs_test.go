@@ -0,0 +1,171 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+)
+
+const printfVerbsSrc = `package test
+
+import (
+	"log"
+	"log/slog"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+)
+
+var (
+	_ = log.Print
+	_ = slog.Info
+	_ = zap.L
+)
+
+func f(logger *zap.Logger, sugar *zap.SugaredLogger, zl zerolog.Logger, name string, count int, err error) {
+	BODY
+}
+`
+
+func TestPrintfVerbsRule(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "slog",
+			body: `slog.Info("processed %d items", count)`,
+			want: "slog.Info doesn't format its message, %d is logged literally",
+		},
+		{
+			name: "zap fields",
+			body: `logger.Info("user %s", zap.String("name", name))`,
+			want: "logger.Info doesn't format its message, %s is logged literally",
+		},
+		{
+			name: "sugar",
+			body: `sugar.Info("user %s", name)`,
+			want: "sugar.Info doesn't format its message, %s is logged literally",
+		},
+		{
+			name: "zerolog without arguments",
+			body: `zl.Info().Msg("retrying in %v")`,
+			want: "zl.Info().Msg doesn't format its message, %v is logged literally",
+		},
+		{
+			name: "log",
+			body: `log.Println("failed: %v", err)`,
+			want: "log.Println doesn't format its message, %v is logged literally",
+		},
+		{
+			name: "percent signs",
+			body: `slog.Info("50% done", "count", count); slog.Info("100%"); slog.Info("100%% done")`,
+		},
+		{
+			name: "printf methods",
+			body: `sugar.Infof("user %s", name); zl.Info().Msgf("user %s", name)`,
+		},
+		{
+			name: "unknown verbs",
+			body: `slog.Info("got 20%z discount")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := checkSource(t, &PrintfVerbsRule{}, strings.Replace(printfVerbsSrc, "BODY", tt.body, 1))
+
+			if tt.want == "" {
+				if len(diagnostics) != 0 {
+					t.Fatalf("Check() reported %q, want nothing", diagnostics[0].Message)
+				}
+				return
+			}
+
+			if len(diagnostics) != 1 {
+				t.Fatalf("Check() reported %d diagnostics, want 1", len(diagnostics))
+			}
+			if !strings.Contains(diagnostics[0].Message, tt.want) {
+				t.Errorf("Check() message = %q, want %q", diagnostics[0].Message, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintfVerbsRuleFix(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "slog",
+			body: `slog.Info("processed %d items", count)`,
+			want: []string{`slog.Info("processed items", "count", count)`},
+		},
+		{
+			name: "slog with attributes",
+			body: `slog.Warn("user %s failed", name, "err", err)`,
+			want: []string{`slog.Warn("user failed", "name", name, "err", err)`},
+		},
+		{
+			name: "sugar",
+			body: `sugar.Info("user %s", name)`,
+			want: []string{`sugar.Infow("user", "name", name)`, `sugar.Infof("user %s", name)`},
+		},
+		{
+			name: "sugar pairs",
+			body: `sugar.Infow("user %s", name)`,
+			want: []string{`sugar.Infow("user", "name", name)`},
+		},
+		{
+			name: "log",
+			body: `log.Print("failed: %v", err)`,
+			want: []string{`log.Printf("failed: %v", err)`},
+		},
+		{
+			name: "arguments forming a pair",
+			body: `slog.Info("rate %s", "key", name)`,
+		},
+		{
+			name: "dangling value after the verbs",
+			body: `slog.Info("user %s", name, err)`,
+		},
+		{
+			name: "missing arguments",
+			body: `slog.Info("processed %d items")`,
+		},
+		{
+			name: "zap fields",
+			body: `logger.Info("user %s", zap.String("name", name))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := strings.Replace(printfVerbsSrc, "BODY", tt.body, 1)
+			pass, file := newTestPass(t, src)
+
+			var fixed []string
+			for _, logCall := range loggers.NewDetector(pass).DetectLogCalls(file) {
+				for _, diag := range (&PrintfVerbsRule{}).Check(pass, logCall) {
+					call := logCall.Call
+					start := pass.Fset.Position(call.Pos()).Offset
+					end := pass.Fset.Position(call.End()).Offset
+
+					for _, fix := range diag.SuggestedFixes {
+						text := applyFix(pass, src, fix)
+						fixed = append(fixed, text[start:end+len(text)-len(src)])
+					}
+				}
+			}
+
+			if !slices.Equal(fixed, tt.want) {
+				t.Errorf("fixed calls = %q, want %q", fixed, tt.want)
+			}
+		})
+	}
+}
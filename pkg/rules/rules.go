@@ -23,6 +23,19 @@ func (rs *RuleSet) CheckLogCall(pass *analysis.Pass, logCall loggers.LogCall) []
 	return diagnostics
 }
 
+// CheckPackage runs the rules that check declarations over a package.
+func (rs *RuleSet) CheckPackage(pass *analysis.Pass) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	for _, rule := range rs.rules {
+		if checker, ok := rule.(PackageChecker); ok {
+			diagnostics = append(diagnostics, checker.CheckPackage(pass)...)
+		}
+	}
+
+	return diagnostics
+}
+
 type RuleSet struct {
 	rules []Rule
 }
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"github.com/hel1th/loglinter/pkg/semconv"
+	"golang.org/x/tools/go/analysis"
+)
+
+// SemconvRule suggests the OpenTelemetry semantic conventions key for
+// attributes logged under an ad hoc key, like http.request.method for
+// method, or under the name an attribute had in another release of the
+// conventions. Keys written as literals get a fix renaming them.
+type SemconvRule struct {
+	conventions *semconv.Conventions
+}
+
+func (r *SemconvRule) Name() string {
+	return "otel-semconv"
+}
+
+func (r *SemconvRule) Message() string {
+	return "attribute key isn't a semantic conventions key"
+}
+
+// SetVersion selects the release of the semantic conventions, the latest
+// bundled one if version is empty.
+func (r *SemconvRule) SetVersion(version string) error {
+	conventions, err := semconv.Lookup(version)
+	if err != nil {
+		return err
+	}
+
+	r.conventions = conventions
+	return nil
+}
+
+// Conventions returns the release of the semantic conventions checked.
+func (r *SemconvRule) Conventions() *semconv.Conventions {
+	if r.conventions == nil {
+		conventions, _ := semconv.Lookup(semconv.Latest)
+		return conventions
+	}
+
+	return r.conventions
+}
+
+func (r *SemconvRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	return r.checkAttrs(logCall.Attrs)
+}
+
+// CheckPackage checks the keys added by With calls.
+func (r *SemconvRule) CheckPackage(pass *analysis.Pass) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	for _, withCall := range detectWithCalls(pass) {
+		diagnostics = append(diagnostics, r.checkAttrs(withCall.Attrs)...)
+	}
+
+	return diagnostics
+}
+
+func (r *SemconvRule) checkAttrs(attrs []loggers.Attr) []analysis.Diagnostic {
+	conventions := r.Conventions()
+
+	var diagnostics []analysis.Diagnostic
+
+	walkAttrs(attrs, "", func(attr loggers.Attr, path string) {
+		// keys implied by constructors like zap.Error can't be renamed
+		if attr.KeyExpr == nil || attr.Key == "" || len(attr.Group) > 0 {
+			return
+		}
+
+		canonical, ok := canonicalKey(conventions, path)
+		if !ok {
+			return
+		}
+
+		diag := analysis.Diagnostic{
+			Pos:      attr.KeyExpr.Pos(),
+			End:      attr.KeyExpr.End(),
+			Message:  fmt.Sprintf("%s: use %q rather than %q (semantic conventions %s)", r.Message(), canonical, path, conventions.Version),
+			Category: r.Name(),
+		}
+
+		// members of a group keep their group
+		group := strings.TrimSuffix(path, attr.Key)
+		if renamed, ok := strings.CutPrefix(canonical, group); ok {
+			if edit, ok := renameKeyEdit(attr.KeyExpr, renamed); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   fmt.Sprintf("Rename the key to %q", canonical),
+					TextEdits: []analysis.TextEdit{edit},
+				}}
+			}
+		}
+
+		diagnostics = append(diagnostics, diag)
+	})
+
+	return diagnostics
+}
+
+// canonicalKey returns the conventions key an attribute should be logged
+// under instead of key. Keys are matched as written, then by their words,
+// so that userID matches user_id.
+func canonicalKey(conventions *semconv.Conventions, key string) (string, bool) {
+	if slices.Contains(conventions.Keys, key) {
+		return "", false
+	}
+
+	if canonical, ok := conventions.Renames[key]; ok {
+		return canonical, true
+	}
+
+	canonical, ok := conventions.Renames[strings.Join(keyWords(key), "_")]
+	return canonical, ok
+}
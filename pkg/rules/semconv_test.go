@@ -0,0 +1,154 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSemconvRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		body    string
+		want    []string
+	}{
+		{
+			name: "synonyms",
+			body: `slog.Info("request", "method", "GET", "status_code", 200)`,
+			want: []string{
+				`use "http.request.method" rather than "method"`,
+				`use "http.response.status_code" rather than "status_code"`,
+			},
+		},
+		{
+			name: "conventions keys",
+			body: `slog.Info("request", "http.request.method", "GET", "url.path", "/", "order_id", id)`,
+		},
+		{
+			name: "renamed key",
+			body: `logger.Info("request", zap.String("http.method", "GET"))`,
+			want: []string{`use "http.request.method" rather than "http.method" (semantic conventions 1.32.0)`},
+		},
+		{
+			name: "keys matched by words",
+			body: `zl.Info().Str("userID", id).Msg("login")`,
+			want: []string{`use "user.id" rather than "userID"`},
+		},
+		{
+			name:    "older release",
+			version: "1.26.0",
+			body:    `zl.Info().Str("userID", id).Str("user.id", id).Msg("login")`,
+			want:    []string{`use "enduser.id" rather than "userID"`, `use "enduser.id" rather than "user.id"`},
+		},
+		{
+			name:    "attribute missing from the release",
+			version: "1.31.0",
+			body:    `slog.Error("failed", "err", err)`,
+		},
+		{
+			name: "error message",
+			body: `slog.Error("failed", "err", err)`,
+			want: []string{`use "error.message" rather than "err"`},
+		},
+		{
+			name: "implied keys",
+			body: `logger.Error("failed", zap.Error(err)); logrus.WithError(err).Error("failed")`,
+		},
+		{
+			name: "groups",
+			body: `slog.Info("request", slog.Group("http", "method", "GET"))`,
+			want: []string{`use "http.request.method" rather than "http.method"`},
+		},
+		{
+			name: "With chain reported once",
+			body: `l := slog.With("env", "prod"); l.Info("a"); l.Info("b")`,
+			want: []string{`use "deployment.environment.name" rather than "env"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &SemconvRule{}
+			if err := rule.SetVersion(tt.version); err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, diagnostics := checkKeys(t, rule, tt.body)
+
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("Check() reported %d diagnostics, want %d: %v", len(diagnostics), len(tt.want), diagnostics)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(diagnostics[i].Message, want) {
+					t.Errorf("Check() message = %q, want %q", diagnostics[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSemconvRuleFix(t *testing.T) {
+	body := `slog.Info("request", "method", "GET", userKey, id, slog.Group("http", "status_code", 200))`
+	want := []string{
+		`slog.Info("request", "http.request.method", "GET", userKey, id, slog.Group("http", "status_code", 200))`,
+		`slog.Info("request", "method", "GET", userKey, id, slog.Group("http", "response.status_code", 200))`,
+	}
+
+	pass, src, diagnostics := checkKeys(t, &SemconvRule{}, body)
+	start := strings.Index(src, body)
+
+	var fixed []string
+	for _, diag := range diagnostics {
+		for _, fix := range diag.SuggestedFixes {
+			text := applyFix(pass, src, fix)
+			fixed = append(fixed, text[start:start+len(body)+len(text)-len(src)])
+		}
+	}
+
+	if len(diagnostics) != 3 {
+		t.Errorf("Check() reported %d diagnostics, want 3: %v", len(diagnostics), diagnostics)
+	}
+	if !slices.Equal(fixed, want) {
+		t.Errorf("fixed calls = %q, want %q", fixed, want)
+	}
+}
+
+func TestSemconvRuleUnknownVersion(t *testing.T) {
+	if err := (&SemconvRule{}).SetVersion("1.2"); err == nil {
+		t.Error("SetVersion(1.2) succeeded, want an error")
+	}
+}
+
+func TestSemconvRuleWithKeyStyle(t *testing.T) {
+	semconvRule := &SemconvRule{}
+	styleRule := &KeyStyleRule{}
+	styleRule.SetConventions(semconvRule.Conventions())
+
+	src := strings.Replace(keyStyleSrc, "BODY", `slog.Info("request", "http.request.method", "GET", "userID", id, slog.Group("http", "statusCode", 200), "requestID", id)`, 1)
+	want := strings.Replace(keyStyleSrc, "BODY", `slog.Info("request", "http.request.method", "GET", "user.id", id, slog.Group("http", "response.status_code", 200), "request_id", id)`, 1)
+
+	// fixes of either rule must not be undone by the other
+	for range 10 {
+		fixed := false
+		for _, rule := range []Rule{styleRule, semconvRule} {
+			pass, _, diagnostics := checkAttrSource(t, rule, src)
+			if len(diagnostics) == 0 {
+				continue
+			}
+			if len(diagnostics[0].SuggestedFixes) == 0 {
+				t.Fatalf("Check() reported %q without a fix", diagnostics[0].Message)
+			}
+			src = applyFix(pass, src, diagnostics[0].SuggestedFixes[0])
+			fixed = true
+			break
+		}
+		if !fixed {
+			break
+		}
+	}
+
+	if src != want {
+		t.Errorf("fixed source = %s, want %s", src, want)
+	}
+}
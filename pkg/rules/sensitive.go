@@ -1,17 +1,39 @@
 package rules
 
 import (
-	"fmt"
 	"go/ast"
-	"strings"
 
+	"github.com/hel1th/loglinter/pkg/compliance"
+	"github.com/hel1th/loglinter/pkg/dictionaries"
 	"github.com/hel1th/loglinter/pkg/loggers"
+	"github.com/hel1th/loglinter/pkg/secrets"
+	"github.com/hel1th/loglinter/pkg/taint"
 	"golang.org/x/tools/go/analysis"
 )
 
 type SensitiveDataRule struct {
 	// Можно добавить кастомные паттерны
 	customPatterns []string
+	// phrases not reported in addition to DefaultBenignPhrases
+	benignPhrases []string
+
+	taintSources []taint.Source
+	// taint analysis result of the package being checked
+	taintPass   *analysis.Pass
+	taintResult *taint.Result
+
+	// detectors of secret values, nil for the defaults
+	secretDetectors []*secrets.Detector
+
+	minConfidence    int
+	severities       map[Category]Severity
+	customCategories map[string]Category
+
+	dictionaries []dictionaries.Dictionary
+
+	profiles []compliance.Profile
+	// findings falling under profiles, for compliance reports
+	evidence []compliance.Evidence
 }
 
 func (r *SensitiveDataRule) Name() string {
@@ -23,75 +45,31 @@ func (r *SensitiveDataRule) Message() string {
 }
 
 func (r *SensitiveDataRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
-	violations := r.analyzeMessageExpression(logCall.Message)
-
-	if len(violations) > 0 {
-		return []analysis.Diagnostic{
-			{
-				Pos:      logCall.Message.Pos(),
-				End:      logCall.Message.End(),
-				Message:  fmt.Sprintf("%s: %s", r.Message(), strings.Join(violations, ", ")),
-				Category: r.Name(),
-			},
-		}
-	}
-
-	return nil
-}
-
-func (r *SensitiveDataRule) analyzeMessageExpression(expr ast.Expr) []string {
-	var violations []string
-
-	switch v := expr.(type) {
-	case *ast.BasicLit:
-		if message, ok := loggers.ExtractStringLit(expr); ok {
-			if keywords := r.findSensitiveKeywords(message); len(keywords) > 0 {
-				violations = append(violations, keywords...)
-			}
-		}
+	var reported []ast.Expr
 
-	case *ast.BinaryExpr:
-		violations = append(violations, r.analyzeBinaryExpr(v)...)
-
-	case *ast.CallExpr:
-		violations = append(violations, r.analyzeCallExpr(v)...)
-	}
-
-	return violations
-}
-
-func (r *SensitiveDataRule) analyzeBinaryExpr(expr *ast.BinaryExpr) []string {
-	var violations []string
-
-	if message, ok := loggers.ExtractStringLit(expr.X); ok {
-		if keywords := r.findSensitiveKeywords(message); len(keywords) > 0 {
-
-			violations = append(violations, fmt.Sprintf("concatenating sensitive field: %s", strings.Join(keywords, ", ")))
-		}
+	findings := r.checkMessage(logCall)
+	if len(findings) > 0 {
+		reported = append(reported, logCall.Message)
 	}
 
-	violations = append(violations, r.analyzeMessageExpression(expr.Y)...)
+	identFindings, identExprs := r.checkIdents(pass, logCall, reported)
+	findings = append(findings, identFindings...)
+	reported = append(reported, identExprs...)
 
-	return violations
-}
+	secretFindings, secretExprs := r.checkSecrets(pass, logCall)
+	findings = append(findings, secretFindings...)
+	reported = append(reported, secretExprs...)
 
-func (r *SensitiveDataRule) analyzeCallExpr(expr *ast.CallExpr) []string {
-	var violations []string
+	attrFindings, attrValues := r.checkAttrs(pass, logCall.Attrs)
+	findings = append(findings, attrFindings...)
+	reported = append(reported, attrValues...)
 
-	if len(expr.Args) > 0 {
-		if message, ok := loggers.ExtractStringLit(expr.Args[0]); ok {
-			if keywords := r.findSensitiveKeywords(message); len(keywords) > 0 {
-				violations = append(violations, keywords...)
-			}
-		}
-	}
+	findings = append(findings, r.checkTaint(pass, logCall, reported)...)
 
-	return violations
+	return r.report(findings)
 }
 
-func (r *SensitiveDataRule) findSensitiveKeywords(message string) []string {
-	var found []string
-	messageLower := strings.ToLower(message)
+func (r *SensitiveDataRule) keywords() []string {
 	sensitiveKeywords := []string{
 		"password", "passwd", "pwd",
 		"token", "access_token", "refresh_token", "bearer",
@@ -102,67 +80,17 @@ func (r *SensitiveDataRule) findSensitiveKeywords(message string) []string {
 		"ssn", "social security",
 		"auth", "authorization",
 		"credentials", "credential",
+		"dsn",
+		"iban",
+		"medical_record", "health_record", "diagnosis",
 	}
 
-	sensitiveKeywords = append(sensitiveKeywords, r.customPatterns...)
+	sensitiveKeywords = append(sensitiveKeywords, r.dictionaryKeywords()...)
+	sensitiveKeywords = append(sensitiveKeywords, r.profileKeywords()...)
 
-	for _, keyword := range sensitiveKeywords {
-		if r.containsSensitivePattern(messageLower, keyword) {
-			found = append(found, keyword)
-		}
-	}
-
-	return found
-}
-
-func (r *SensitiveDataRule) containsSensitivePattern(message, keyword string) bool {
-	patterns := []string{
-		keyword + ":",
-		keyword + " :",
-		keyword + "=",
-		keyword + " =",
-		keyword + " is",
-	}
-
-	for _, pattern := range patterns {
-		if strings.Contains(message, pattern) {
-			return true
-		}
-	}
-
-	words := strings.Fields(message)
-	for _, word := range words {
-		cleaned := strings.Trim(word, ".,;:!?\"'")
-		if cleaned == keyword {
-			return true
-		}
-	}
-
-	return false
-}
-
-func (r *SensitiveDataRule) CheckExpr(expr ast.Expr) (bool, string) {
-	violations := r.analyzeMessageExpression(expr)
-
-	if len(violations) > 0 {
-		return false, strings.Join(violations, ", ")
-	}
-
-	return true, ""
+	return append(sensitiveKeywords, r.customPatterns...)
 }
 
 func (r *SensitiveDataRule) SetCustomPatterns(patterns []string) {
 	r.customPatterns = patterns
 }
-
-func (r *SensitiveDataRule) GetDefaultPatterns() []string {
-	return []string{
-		"password", "passwd", "pwd",
-		"token", "access_token", "refresh_token",
-		"api_key", "apikey",
-		"secret", "client_secret",
-		"private_key", "privatekey",
-		"credit_card", "cvv",
-		"credentials",
-	}
-}
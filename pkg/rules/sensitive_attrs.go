@@ -0,0 +1,136 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+const redactedValue = `"[REDACTED]"`
+
+// checkAttrs reports attributes whose keys name sensitive data, including
+// keys nested in groups. It also returns the reported attributes.
+func (r *SensitiveDataRule) checkAttrs(pass *analysis.Pass, attrs []loggers.Attr) ([]finding, []ast.Expr) {
+	var (
+		findings []finding
+		reported []ast.Expr
+	)
+
+	for _, attr := range attrs {
+		if attr.KeyExpr != nil && attr.Key != "" {
+			if keywords := r.findSensitiveKeys(attr.Key); len(keywords) > 0 {
+				// a constant value like "[REDACTED]" exposes nothing
+				ev := evidenceKeyword
+				if attr.Value != nil && !isConstant(pass, attr.Value) {
+					ev = evidenceKeywordValue
+				}
+				f := newFinding(r.attrDiagnostic(pass, attr, keywords), r.categoryOf(keywords[0]), ev)
+				f.profiles = r.keywordProfiles(keywords)
+				findings = append(findings, f)
+				reported = append(reported, attr.Expr)
+				if attr.Value != nil {
+					reported = append(reported, attr.Value)
+				}
+			}
+		}
+
+		groupFindings, groupReported := r.checkAttrs(pass, attr.Group)
+		findings = append(findings, groupFindings...)
+		reported = append(reported, groupReported...)
+	}
+
+	return findings, reported
+}
+
+func (r *SensitiveDataRule) attrDiagnostic(pass *analysis.Pass, attr loggers.Attr, keywords []string) analysis.Diagnostic {
+	diag := analysis.Diagnostic{
+		Pos:      attr.KeyExpr.Pos(),
+		End:      attr.KeyExpr.End(),
+		Message:  fmt.Sprintf("log attribute %q may contain sensitive data: %s", attr.Key, strings.Join(keywords, ", ")),
+		Category: r.Name(),
+	}
+
+	if edits, ok := redactEdits(pass, attr); ok {
+		diag.SuggestedFixes = []analysis.SuggestedFix{
+			{
+				Message:   "Redact the attribute value",
+				TextEdits: edits,
+			},
+		}
+	}
+
+	return diag
+}
+
+func isConstant(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	return ok && tv.Value != nil
+}
+
+// redactEdits replace the value of an attribute with a placeholder. Typed
+// constructors like zap.Int can't hold a string, so they are renamed to
+// the String constructor of the same package, or to Str for zerolog.
+func redactEdits(pass *analysis.Pass, attr loggers.Attr) ([]analysis.TextEdit, bool) {
+	if attr.Value == nil || attr.Group != nil {
+		return nil, false
+	}
+
+	edits := []analysis.TextEdit{{
+		Pos:     attr.Value.Pos(),
+		End:     attr.Value.End(),
+		NewText: []byte(redactedValue),
+	}}
+
+	if attr.Func == nil || acceptsString(attr.Func) {
+		return edits, true
+	}
+
+	call, ok := ast.Unparen(attr.Expr).(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+
+	// zerolog's field methods are named Str
+	constructor := "String"
+	if attr.Func.Type().(*types.Signature).Recv() != nil {
+		constructor = "Str"
+	}
+
+	rename := analysis.TextEdit{Pos: sel.Sel.Pos(), End: sel.Sel.End(), NewText: []byte(constructor)}
+
+	return append([]analysis.TextEdit{rename}, edits...), true
+}
+
+// acceptsString reports whether the value parameter of an attribute
+// constructor can hold a string.
+func acceptsString(fn *types.Func) bool {
+	params := fn.Type().(*types.Signature).Params()
+	if params.Len() < 2 {
+		return false
+	}
+
+	switch t := params.At(1).Type().Underlying().(type) {
+	case *types.Basic:
+		return t.Info()&types.IsString != 0
+	case *types.Interface:
+		return t.Empty()
+	}
+
+	return false
+}
+
+// findSensitiveKeys matches an attribute key or identifier against the
+// sensitive keywords. Keys match when they contain a keyword as separate
+// words, like apiKey, api_key and user_password.
+func (r *SensitiveDataRule) findSensitiveKeys(key string) []string {
+	return r.matchKeywords(key)
+}
@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/compliance"
+)
+
+// SetProfiles adds the keywords, types and detectors of compliance profiles
+// and makes findings name the profiles they fall under.
+func (r *SensitiveDataRule) SetProfiles(profiles []compliance.Profile) {
+	r.profiles = profiles
+}
+
+// Evidence returns the findings of every log call checked so far that fall
+// under a compliance profile, including ones below the minimum confidence.
+func (r *SensitiveDataRule) Evidence() []compliance.Evidence {
+	return r.evidence
+}
+
+func (r *SensitiveDataRule) profileKeywords() []string {
+	var keywords []string
+	for _, p := range r.profiles {
+		keywords = append(keywords, p.Keywords...)
+	}
+
+	return keywords
+}
+
+// profileCategory returns the category of a keyword of a profile.
+func (r *SensitiveDataRule) profileCategory(keyword string) (Category, bool) {
+	for _, p := range r.profiles {
+		if slices.Contains(p.Keywords, keyword) {
+			return Category(p.Category), true
+		}
+	}
+
+	return "", false
+}
+
+// keywordProfiles returns the profiles covering any of the keywords.
+func (r *SensitiveDataRule) keywordProfiles(keywords []string) []string {
+	var names []string
+
+	for _, p := range r.profiles {
+		if coversKeyword(p, keywords) {
+			names = append(names, p.Name)
+		}
+	}
+
+	return names
+}
+
+func coversKeyword(p compliance.Profile, keywords []string) bool {
+	return slices.ContainsFunc(keywords, func(keyword string) bool {
+		return slices.Contains(p.Keywords, keyword)
+	})
+}
+
+func (r *SensitiveDataRule) detectorProfiles(detector string) []string {
+	var names []string
+
+	for _, p := range r.profiles {
+		if slices.Contains(p.Detectors, detector) {
+			names = append(names, p.Name)
+		}
+	}
+
+	return names
+}
+
+// typeProfiles returns the profiles covering a type, either listing it or
+// having a keyword in its name, like type PhoneNumber string.
+func (r *SensitiveDataRule) typeProfiles(typ types.Type) []string {
+	if typ == nil {
+		return nil
+	}
+
+	qualified := qualifiedName(typ)
+	if qualified == "" {
+		return nil
+	}
+	keywords := r.matchKeywords(qualified[strings.LastIndex(qualified, ".")+1:])
+
+	var names []string
+	for _, p := range r.profiles {
+		if slices.Contains(p.Types, qualified) || coversKeyword(p, keywords) {
+			names = append(names, p.Name)
+		}
+	}
+
+	return names
+}
+
+// record keeps a finding as evidence for each profile it falls under.
+func (r *SensitiveDataRule) record(f finding) {
+	for _, profile := range f.profiles {
+		r.evidence = append(r.evidence, compliance.Evidence{
+			Profile: profile,
+			Pos:     f.diag.Pos,
+			End:     f.diag.End,
+			Message: f.diag.Message,
+		})
+	}
+}
+
+func (r *SensitiveDataRule) profileCategoryOf(name string) Category {
+	for _, p := range r.profiles {
+		if p.Name == name {
+			return Category(p.Category)
+		}
+	}
+
+	return CategoryCredential
+}
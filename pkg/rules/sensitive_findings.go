@@ -0,0 +1,262 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+// Category is the kind of sensitive data a finding is about.
+type Category string
+
+const (
+	CategoryCredential Category = "credential"
+	CategoryPII        Category = "pii"
+	CategoryFinancial  Category = "financial"
+	CategoryHealth     Category = "health"
+)
+
+var categories = []Category{CategoryCredential, CategoryPII, CategoryFinancial, CategoryHealth}
+
+func ParseCategory(s string) (Category, error) {
+	if !slices.Contains(categories, Category(s)) {
+		return "", fmt.Errorf("unknown sensitive data category %q, want one of %s", s, joinCategories())
+	}
+
+	return Category(s), nil
+}
+
+func joinCategories() string {
+	names := make([]string, len(categories))
+	for i, c := range categories {
+		names[i] = string(c)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// Severity is how a finding of a category is reported.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	// findings of the category aren't reported
+	SeverityOff Severity = "off"
+)
+
+func ParseSeverity(s string) (Severity, error) {
+	switch severity := Severity(s); severity {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return severity, nil
+	}
+
+	return "", fmt.Errorf("unknown severity %q, want error, warning, info or off", s)
+}
+
+// DefaultSeverities are used for categories without a configured severity.
+var DefaultSeverities = map[Category]Severity{
+	CategoryCredential: SeverityError,
+	CategoryFinancial:  SeverityError,
+	CategoryPII:        SeverityWarning,
+	CategoryHealth:     SeverityWarning,
+}
+
+// evidence is what a finding is based on, from weakest to strongest.
+type evidence int
+
+const (
+	// a keyword in constant text, like "reset pwd"
+	evidenceKeyword evidence = iota
+	// a value whose name contains a keyword, like userPassword
+	evidenceNamedValue
+	// a value derived from a sensitive source
+	evidenceTainted
+	// a keyword followed by a separator and a dynamic value, like
+	// "password=" + pw
+	evidenceKeywordValue
+	// a secret value written in the code
+	evidenceSecret
+)
+
+// confidence scores evidence in percent.
+func (e evidence) confidence() int {
+	switch e {
+	case evidenceKeyword:
+		return 40
+	case evidenceNamedValue:
+		return 70
+	case evidenceTainted:
+		return 75
+	case evidenceKeywordValue:
+		return 85
+	default:
+		return 95
+	}
+}
+
+// finding is a diagnostic of no-sensitive-data before it is scored.
+type finding struct {
+	diag       analysis.Diagnostic
+	category   Category
+	confidence int
+	// compliance profiles the finding falls under
+	profiles []string
+}
+
+func newFinding(diag analysis.Diagnostic, category Category, ev evidence) finding {
+	return finding{diag: diag, category: category, confidence: ev.confidence()}
+}
+
+func (r *SensitiveDataRule) SetMinConfidence(confidence int) {
+	r.minConfidence = confidence
+}
+
+func (r *SensitiveDataRule) SetSeverities(severities map[Category]Severity) {
+	r.severities = severities
+}
+
+// SetCustomCategories sets the categories of custom patterns. Patterns
+// without one are credentials.
+func (r *SensitiveDataRule) SetCustomCategories(categories map[string]Category) {
+	r.customCategories = categories
+}
+
+func (r *SensitiveDataRule) severity(category Category) Severity {
+	if severity, ok := r.severities[category]; ok {
+		return severity
+	}
+
+	return DefaultSeverities[category]
+}
+
+// report turns findings into diagnostics stating their category, confidence,
+// severity and compliance profiles. Findings below the minimum confidence or
+// of categories turned off are dropped, but still kept as evidence.
+func (r *SensitiveDataRule) report(findings []finding) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	for _, f := range findings {
+		r.record(f)
+
+		severity := r.severity(f.category)
+		if f.confidence < r.minConfidence || severity == SeverityOff {
+			continue
+		}
+
+		diag := f.diag
+		if len(f.profiles) > 0 {
+			diag.Message = fmt.Sprintf("%s [%s, confidence %d%%, %s, compliance: %s]",
+				diag.Message, f.category, f.confidence, severity, strings.Join(f.profiles, ", "))
+		} else {
+			diag.Message = fmt.Sprintf("%s [%s, confidence %d%%, %s]", diag.Message, f.category, f.confidence, severity)
+		}
+		diagnostics = append(diagnostics, diag)
+	}
+
+	return diagnostics
+}
+
+var keywordCategories = map[string]Category{
+	"credit_card":     CategoryFinancial,
+	"card_number":     CategoryFinancial,
+	"cvv":             CategoryFinancial,
+	"cvc":             CategoryFinancial,
+	"iban":            CategoryFinancial,
+	"ssn":             CategoryPII,
+	"social security": CategoryPII,
+	"diagnosis":       CategoryHealth,
+	"medical_record":  CategoryHealth,
+}
+
+// categoryOf returns the category of a keyword.
+func (r *SensitiveDataRule) categoryOf(keyword string) Category {
+	if category, ok := r.customCategories[keyword]; ok {
+		return category
+	}
+	if category, ok := r.profileCategory(keyword); ok {
+		return category
+	}
+	if category, ok := r.dictionaryCategory(keyword); ok {
+		return category
+	}
+	if category, ok := keywordCategories[keyword]; ok {
+		return category
+	}
+
+	return CategoryCredential
+}
+
+// checkMessage reports keywords in the text of the message, grouped by
+// category. A keyword right before a separator and a dynamic value, like
+// "password=%s", is stronger evidence than a keyword alone.
+func (r *SensitiveDataRule) checkMessage(logCall loggers.LogCall) []finding {
+	var (
+		order    []Category
+		keywords = make(map[Category][]string)
+		strength = make(map[Category]evidence)
+	)
+
+	segments := logCall.Text.Segments
+	for i, seg := range segments {
+		if seg.Kind != loggers.LiteralSegment {
+			continue
+		}
+
+		valueFollows := i+1 < len(segments) && segments[i+1].Kind != loggers.LiteralSegment
+
+		for _, keyword := range r.matchKeywords(seg.Text) {
+			category := r.categoryOf(keyword)
+			if _, ok := keywords[category]; !ok {
+				order = append(order, category)
+			}
+			if !slices.Contains(keywords[category], keyword) {
+				keywords[category] = append(keywords[category], keyword)
+			}
+
+			ev := evidenceKeyword
+			if valueFollows && assigns(seg.Text, keyword) {
+				ev = evidenceKeywordValue
+			}
+			strength[category] = max(strength[category], ev)
+		}
+	}
+
+	var findings []finding
+	for _, category := range order {
+		f := newFinding(analysis.Diagnostic{
+			Pos:      logCall.Message.Pos(),
+			End:      logCall.Message.End(),
+			Message:  fmt.Sprintf("%s: %s", r.Message(), strings.Join(keywords[category], ", ")),
+			Category: r.Name(),
+		}, category, strength[category])
+		f.profiles = r.keywordProfiles(keywords[category])
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// assigns reports whether text ends with a keyword and a separator that
+// introduces a value, like "password: ", "token=" or "secret is".
+func assigns(text, keyword string) bool {
+	text = strings.TrimRight(strings.ToLower(text), " ")
+
+	trimmed := false
+	for _, sep := range []string{":", "=", " is", "-"} {
+		if strings.HasSuffix(text, sep) {
+			text, trimmed = strings.TrimSuffix(text, sep), true
+			break
+		}
+	}
+	if !trimmed {
+		return false
+	}
+
+	words, keywordWords := splitWords(text), splitWords(keyword)
+	return len(words) >= len(keywordWords) && slices.Equal(words[len(words)-len(keywordWords):], keywordWords)
+}
@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/hel1th/loglinter/pkg/dictionaries"
+	"golang.org/x/text/cases"
+)
+
+// SetLanguages adds the keywords of dictionaries in other languages to the
+// English ones.
+func (r *SensitiveDataRule) SetLanguages(dicts []dictionaries.Dictionary) {
+	r.dictionaries = dicts
+}
+
+func (r *SensitiveDataRule) dictionaryKeywords() []string {
+	var keywords []string
+	for _, d := range r.dictionaries {
+		for _, group := range d.Keywords {
+			keywords = append(keywords, group.Words...)
+		}
+	}
+
+	return keywords
+}
+
+// dictionaryCategory returns the category of a keyword of a dictionary.
+func (r *SensitiveDataRule) dictionaryCategory(keyword string) (Category, bool) {
+	for _, d := range r.dictionaries {
+		for _, group := range d.Keywords {
+			if slices.Contains(group.Words, keyword) {
+				return Category(group.Category), true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// homoglyphs map Cyrillic and Greek letters to the Latin letters they look
+// like once case folded.
+var homoglyphs = map[rune]rune{
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd',
+	'ԛ': 'q', 'ԝ': 'w',
+	'α': 'a', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u',
+}
+
+// foldWord case folds a word. Words mixing Latin letters with lookalikes
+// from other scripts, like pаsswоrd with a Cyrillic а and о, are spelled in
+// Latin letters, while words written in one script are left as they are.
+func foldWord(caser cases.Caser, word string) string {
+	word = caser.String(word)
+
+	latin, other := false, false
+	for _, r := range word {
+		switch {
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			latin = true
+		case unicode.IsLetter(r):
+			other = true
+		}
+	}
+	if !latin || !other {
+		return word
+	}
+
+	runes := []rune(word)
+	for i, r := range runes {
+		if latinLetter, ok := homoglyphs[r]; ok {
+			runes[i] = latinLetter
+		}
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,423 @@
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hel1th/loglinter/pkg/annotations"
+	"github.com/hel1th/loglinter/pkg/dictionaries"
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+// PackageChecker is implemented by rules that also check the declarations
+// of a package, not only its log calls.
+type PackageChecker interface {
+	CheckPackage(pass *analysis.Pass) []analysis.Diagnostic
+}
+
+// formattingMethods are the methods loggers and fmt call to render a value,
+// with their number of parameters and results.
+var formattingMethods = map[string][2]int{
+	"String":           {0, 1},
+	"GoString":         {0, 1},
+	"Format":           {2, 0},
+	"LogValue":         {0, 1},
+	"MarshalLogObject": {1, 1},
+}
+
+// redactingCalls are words in the names of functions whose results don't
+// expose their arguments, like redact, maskPAN or sha256.Sum256. They match
+// whole words, so summary or hidden don't count.
+var redactingCalls = []string{"redact", "mask", "hash", "sum", "obfuscate", "len", "hide", "censor"}
+
+// SensitiveMethodRule checks the formatting methods of sensitive types:
+// String, GoString, Format, LogValue and MarshalLogObject must not return or
+// encode sensitive fields, or the value of an annotated type, unredacted.
+// Annotated types, and types whose formatting methods leak, without a
+// LogValue method get a suggested fix generating a redacting one.
+type SensitiveMethodRule struct {
+	// matches field names against the sensitive keywords
+	keys SensitiveDataRule
+}
+
+func (r *SensitiveMethodRule) Name() string {
+	return "no-sensitive-methods"
+}
+
+func (r *SensitiveMethodRule) Message() string {
+	return "formatting method exposes sensitive data"
+}
+
+func (r *SensitiveMethodRule) SetCustomPatterns(patterns []string) {
+	r.keys.SetCustomPatterns(patterns)
+}
+
+func (r *SensitiveMethodRule) SetBenignPhrases(phrases []string) {
+	r.keys.SetBenignPhrases(phrases)
+}
+
+func (r *SensitiveMethodRule) SetLanguages(dicts []dictionaries.Dictionary) {
+	r.keys.SetLanguages(dicts)
+}
+
+// Check does nothing, the rule checks declarations in CheckPackage.
+func (r *SensitiveMethodRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	return nil
+}
+
+func (r *SensitiveMethodRule) CheckPackage(pass *analysis.Pass) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	// types whose formatting methods leak
+	leaking := make(map[*types.TypeName]bool)
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil || !isFormattingMethod(pass, fn) {
+				continue
+			}
+
+			named := receiverNamed(pass, fn)
+			if named == nil {
+				continue
+			}
+
+			leaks := r.methodLeaks(pass, fn, named)
+			if len(leaks) > 0 {
+				leaking[named.Obj()] = true
+			}
+			diagnostics = append(diagnostics, leaks...)
+		}
+	}
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if diag, ok := r.missingLogValue(pass, file, gen, spec, leaking); ok {
+					diagnostics = append(diagnostics, diag)
+				}
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+func isFormattingMethod(pass *analysis.Pass, fn *ast.FuncDecl) bool {
+	arity, ok := formattingMethods[fn.Name.Name]
+	if !ok {
+		return false
+	}
+
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := obj.Type().(*types.Signature)
+	return sig.Params().Len() == arity[0] && sig.Results().Len() == arity[1]
+}
+
+// receiverNamed returns the named type a method is declared on.
+func receiverNamed(pass *analysis.Pass, fn *ast.FuncDecl) *types.Named {
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	recv := obj.Type().(*types.Signature).Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+
+	named, _ := recv.(*types.Named)
+	return named
+}
+
+// methodLeaks reports the sensitive data a formatting method exposes: uses
+// of sensitive fields, and of the receiver itself if its type is annotated,
+// that aren't redacted, compared or measured first.
+func (r *SensitiveMethodRule) methodLeaks(pass *analysis.Pass, fn *ast.FuncDecl, named *types.Named) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	typeReason, typeSensitive := annotations.Sensitive(pass, named.Obj())
+
+	var recv types.Object
+	if names := fn.Recv.List[0].Names; len(names) > 0 {
+		recv = pass.TypesInfo.Defs[names[0]]
+	}
+
+	report := func(expr ast.Expr, what string) {
+		diagnostics = append(diagnostics, analysis.Diagnostic{
+			Pos:      expr.Pos(),
+			End:      expr.End(),
+			Message:  fmt.Sprintf("%s: %s method of %s exposes %s", r.Message(), fn.Name.Name, named.Obj().Name(), what),
+			Category: r.Name(),
+		})
+	}
+
+	ast.PreorderStack(fn.Body, nil, func(n ast.Node, stack []ast.Node) bool {
+		switch e := n.(type) {
+		case *ast.SelectorExpr:
+			selection, ok := pass.TypesInfo.Selections[e]
+			if !ok || selection.Kind() != types.FieldVal {
+				return true
+			}
+
+			field := selection.Obj()
+			if reason, ok := r.sensitiveField(pass, field); ok && !redacted(pass, e, stack) {
+				report(e, fmt.Sprintf("sensitive field %s%s", field.Name(), reason))
+				return false
+			}
+		case *ast.Ident:
+			if recv == nil || pass.TypesInfo.Uses[e] != recv || redacted(pass, e, stack) {
+				return true
+			}
+			if typeSensitive {
+				report(e, fmt.Sprintf("its sensitive value (%s)", typeReason))
+				return true
+			}
+			// printing the whole receiver, like fmt.Sprintf("%+v", *u),
+			// prints its fields
+			if fields := r.sensitiveFields(pass, named); len(fields) > 0 && wholeValue(e, stack) {
+				report(e, "its sensitive fields "+strings.Join(fields, ", "))
+			}
+		}
+
+		return true
+	})
+
+	return diagnostics
+}
+
+// exposes reports whether a formatting method exposes sensitive data. Only
+// the methods declared in the package are analyzed, those of other packages
+// are trusted.
+func (r *SensitiveMethodRule) exposes(pass *analysis.Pass, method *types.Func) bool {
+	if method.Pkg() != pass.Pkg {
+		return false
+	}
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil || pass.TypesInfo.Defs[fn.Name] != method {
+				continue
+			}
+
+			named := receiverNamed(pass, fn)
+			return named != nil && len(r.methodLeaks(pass, fn, named)) > 0
+		}
+	}
+
+	return false
+}
+
+// sensitiveFields returns the names of the sensitive fields of a struct type.
+func (r *SensitiveMethodRule) sensitiveFields(pass *analysis.Pass, named *types.Named) []string {
+	st, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	var fields []string
+	for field := range st.Fields() {
+		if _, ok := r.sensitiveField(pass, field); ok {
+			fields = append(fields, field.Name())
+		}
+	}
+
+	return fields
+}
+
+// wholeValue reports whether an expression, enclosed by the nodes of stack,
+// is used whole rather than to select one of its fields.
+func wholeValue(expr ast.Expr, stack []ast.Node) bool {
+	var child ast.Node = expr
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.ParenExpr, *ast.StarExpr:
+		case *ast.SelectorExpr:
+			return n.X != child
+		default:
+			return true
+		}
+
+		child = stack[i]
+	}
+
+	return true
+}
+
+// sensitiveField reports whether a field is annotated or named like
+// sensitive data. The reason is empty for named fields.
+func (r *SensitiveMethodRule) sensitiveField(pass *analysis.Pass, field types.Object) (string, bool) {
+	if reason, ok := annotations.Sensitive(pass, field); ok {
+		return " (" + reason + ")", true
+	}
+
+	return "", len(r.keys.findSensitiveKeys(field.Name())) > 0
+}
+
+// redacted reports whether an expression, enclosed by the nodes of stack, is
+// only used through a redacting call, a comparison, or a method called on
+// it. Fields selected from it, like the Raw of u.Token.Raw, are as sensitive.
+func redacted(pass *analysis.Pass, expr ast.Expr, stack []ast.Node) bool {
+	var child ast.Node = expr
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.BinaryExpr:
+			switch n.Op {
+			case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+				return true
+			}
+		case *ast.CallExpr:
+			words := splitWords(funcName(n))
+			if slices.ContainsFunc(redactingCalls, func(word string) bool { return slices.Contains(words, word) }) {
+				return true
+			}
+		case *ast.SelectorExpr:
+			if selection, ok := pass.TypesInfo.Selections[n]; ok && n.X == child && selection.Kind() == types.MethodVal {
+				return true
+			}
+		}
+
+		child = stack[i]
+	}
+
+	return false
+}
+
+// funcName returns the name of the function a call calls.
+func funcName(call *ast.CallExpr) string {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+
+	return ""
+}
+
+// missingLogValue reports a sensitive type without a LogValue method,
+// suggesting a redacting one. Types are sensitive when annotated, when a
+// field is annotated or when their formatting methods leak.
+func (r *SensitiveMethodRule) missingLogValue(pass *analysis.Pass, file *ast.File, gen *ast.GenDecl, spec *ast.TypeSpec, leaking map[*types.TypeName]bool) (analysis.Diagnostic, bool) {
+	obj, ok := pass.TypesInfo.Defs[spec.Name].(*types.TypeName)
+	if !ok || spec.TypeParams != nil || spec.Assign.IsValid() {
+		return analysis.Diagnostic{}, false
+	}
+
+	named, ok := obj.Type().(*types.Named)
+	if !ok || types.IsInterface(named) {
+		return analysis.Diagnostic{}, false
+	}
+
+	if sel := types.NewMethodSet(types.NewPointer(named)).Lookup(obj.Pkg(), "LogValue"); sel != nil {
+		return analysis.Diagnostic{}, false
+	}
+
+	_, typeAnnotated := annotations.Sensitive(pass, obj)
+	annotated := typeAnnotated
+
+	st, isStruct := named.Underlying().(*types.Struct)
+	if isStruct && !annotated {
+		for field := range st.Fields() {
+			if _, ok := annotations.Sensitive(pass, field); ok {
+				annotated = true
+				break
+			}
+		}
+	}
+	if !annotated && !leaking[obj] {
+		return analysis.Diagnostic{}, false
+	}
+
+	diag := analysis.Diagnostic{
+		Pos:      spec.Name.Pos(),
+		End:      spec.Name.End(),
+		Message:  fmt.Sprintf("sensitive type %s has no redacting LogValue method", obj.Name()),
+		Category: r.Name(),
+	}
+
+	slogName, importEdit := importName(file, "log/slog")
+
+	var method bytes.Buffer
+	first, _ := utf8.DecodeRuneInString(obj.Name())
+	recv := string(unicode.ToLower(first))
+	fmt.Fprintf(&method, "\n\n// LogValue redacts the sensitive data of %s in logs.\n", obj.Name())
+	fmt.Fprintf(&method, "func (%s %s) LogValue() %s.Value {\n", recv, obj.Name(), slogName)
+	// values of annotated types are redacted whole
+	if !isStruct || typeAnnotated {
+		fmt.Fprintf(&method, "\treturn %s.StringValue(%s)\n", slogName, redactedValue)
+	} else {
+		fmt.Fprintf(&method, "\treturn %s.GroupValue(\n", slogName)
+		for field := range st.Fields() {
+			if field.Name() == "_" {
+				continue
+			}
+			if _, ok := r.sensitiveField(pass, field); ok {
+				fmt.Fprintf(&method, "\t\t%s.String(%q, %s),\n", slogName, field.Name(), redactedValue)
+			} else {
+				fmt.Fprintf(&method, "\t\t%s.Any(%q, %s.%s),\n", slogName, field.Name(), recv, field.Name())
+			}
+		}
+		fmt.Fprintf(&method, "\t)\n")
+	}
+	fmt.Fprintf(&method, "}")
+
+	edits := []analysis.TextEdit{{Pos: gen.End(), End: gen.End(), NewText: method.Bytes()}}
+	if importEdit != nil {
+		edits = append(edits, *importEdit)
+	}
+	diag.SuggestedFixes = []analysis.SuggestedFix{
+		{
+			Message:   "Add a redacting LogValue method",
+			TextEdits: edits,
+		},
+	}
+
+	return diag, true
+}
+
+// importName returns the name a package is imported as in a file, and an
+// edit importing it if it isn't.
+func importName(file *ast.File, path string) (string, *analysis.TextEdit) {
+	name := path[strings.LastIndex(path, "/")+1:]
+
+	for _, spec := range file.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == path {
+			if spec.Name != nil && spec.Name.Name != "_" && spec.Name.Name != "." {
+				return spec.Name.Name, nil
+			}
+			return name, nil
+		}
+	}
+
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && gen.Lparen.IsValid() {
+			return name, &analysis.TextEdit{Pos: gen.Lparen + 1, End: gen.Lparen + 1, NewText: fmt.Appendf(nil, "\n\t%q", path)}
+		}
+	}
+
+	return name, &analysis.TextEdit{Pos: file.Name.End(), End: file.Name.End(), NewText: fmt.Appendf(nil, "\n\nimport %q", path)}
+}
@@ -0,0 +1,278 @@
+package rules
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/hel1th/loglinter/pkg/annotations"
+)
+
+func TestSensitiveMethodRule(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "string returns sensitive field",
+			src: `type Credentials struct {
+	User     string
+	Password string
+}
+
+func (c Credentials) String() string { return c.User + ":" + c.Password }
+
+func (c Credentials) LogValue() slog.Value { return slog.StringValue(c.User) }
+`,
+			want: []string{"String method of Credentials exposes sensitive field Password"},
+		},
+		{
+			name: "marshal encodes annotated field",
+			src: `type Card struct {
+	Holder string
+	Number string ` + "`" + `loglinter:"sensitive"` + "`" + `
+}
+
+type encoder interface{ AddString(key, value string) }
+
+func (c *Card) MarshalLogObject(enc encoder) error {
+	enc.AddString("holder", c.Holder)
+	enc.AddString("number", c.Number)
+	return nil
+}
+`,
+			want: []string{
+				`MarshalLogObject method of Card exposes sensitive field Number (tag loglinter:"sensitive")`,
+				"sensitive type Card has no redacting LogValue method",
+			},
+		},
+		{
+			name: "annotated type",
+			src: `//loglinter:sensitive
+type Token string
+
+func (t Token) GoString() string { return "Token(" + string(t) + ")" }
+`,
+			want: []string{
+				"GoString method of Token exposes its sensitive value (//loglinter:sensitive)",
+				"sensitive type Token has no redacting LogValue method",
+			},
+		},
+		{
+			name: "format through fmt",
+			src: `type Session struct {
+	ID     string
+	Secret string
+}
+
+func (s Session) Format(f fmt.State, verb rune) { fmt.Fprintf(f, "%s/%s", s.ID, s.Secret) }
+
+func (s Session) LogValue() slog.Value { return slog.StringValue(s.ID) }
+`,
+			want: []string{"Format method of Session exposes sensitive field Secret"},
+		},
+		{
+			name: "redacted, compared and measured",
+			src: `type Credentials struct {
+	User     string
+	Password string
+}
+
+func redact(s string) string { return "***" }
+
+func (c Credentials) String() string {
+	if c.Password == "" {
+		return c.User
+	}
+	return c.User + ":" + redact(c.Password) + fmt.Sprint(len(c.Password))
+}
+
+func (c Credentials) LogValue() slog.Value { return slog.StringValue(c.String()) }
+`,
+		},
+		{
+			name: "fields of sensitive fields",
+			src: `type secret struct{ Raw string }
+
+type User struct {
+	Name  string
+	Token secret
+}
+
+func summary(s string) string { return s }
+
+func (u User) String() string { return u.Name + summary(u.Token.Raw) }
+
+func (u User) LogValue() slog.Value { return slog.StringValue(u.Name) }
+`,
+			want: []string{"String method of User exposes sensitive field Token"},
+		},
+		{
+			name: "whole receiver",
+			src: `type User struct {
+	Name     string
+	Password string
+}
+
+type plain User
+
+func (u *User) String() string { return fmt.Sprintf("%+v", plain(*u)) }
+
+func (u *User) LogValue() slog.Value { return slog.StringValue(u.Name) }
+`,
+			want: []string{"String method of User exposes its sensitive fields Password"},
+		},
+		{
+			name: "methods of other types",
+			src: `type Config struct{ APIKey string }
+
+func (c Config) Validate() string { return c.APIKey }
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := `package test
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+var (
+	_ = fmt.Sprint
+	_ = slog.Info
+)
+
+` + tt.src
+
+			pass, _ := newTestPass(t, src)
+			annotations.Export(pass, annotations.Options{})
+
+			diagnostics := (&SensitiveMethodRule{}).CheckPackage(pass)
+
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("CheckPackage() reported %d diagnostics, want %d: %v", len(diagnostics), len(tt.want), diagnostics)
+			}
+			for i, diag := range diagnostics {
+				if !strings.HasSuffix(diag.Message, tt.want[i]) {
+					t.Errorf("CheckPackage() message = %q, want suffix %q", diag.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSensitiveMethodRuleFix(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "struct",
+			src: `package test
+
+type Card struct {
+	Holder string
+	Number string ` + "`" + `loglinter:"sensitive"` + "`" + `
+	CVV    string
+}
+`,
+			want: `package test
+
+import "log/slog"
+
+type Card struct {
+	Holder string
+	Number string ` + "`" + `loglinter:"sensitive"` + "`" + `
+	CVV    string
+}
+
+// LogValue redacts the sensitive data of Card in logs.
+func (c Card) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("Holder", c.Holder),
+		slog.String("Number", "[REDACTED]"),
+		slog.String("CVV", "[REDACTED]"),
+	)
+}
+`,
+		},
+		{
+			name: "annotated type with imports",
+			src: `package test
+
+import (
+	"fmt"
+)
+
+var _ = fmt.Sprint
+
+//loglinter:sensitive
+type Token string
+`,
+			want: `package test
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+var _ = fmt.Sprint
+
+//loglinter:sensitive
+type Token string
+
+// LogValue redacts the sensitive data of Token in logs.
+func (t Token) LogValue() slog.Value {
+	return slog.StringValue("[REDACTED]")
+}
+`,
+		},
+		{
+			name: "non-ASCII name",
+			src: `package test
+
+//loglinter:sensitive
+type Ключ string
+`,
+			want: `package test
+
+import "log/slog"
+
+//loglinter:sensitive
+type Ключ string
+
+// LogValue redacts the sensitive data of Ключ in logs.
+func (к Ключ) LogValue() slog.Value {
+	return slog.StringValue("[REDACTED]")
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pass, _ := newTestPass(t, tt.src)
+			annotations.Export(pass, annotations.Options{})
+
+			diagnostics := (&SensitiveMethodRule{}).CheckPackage(pass)
+			if len(diagnostics) != 1 || len(diagnostics[0].SuggestedFixes) != 1 {
+				t.Fatalf("CheckPackage() = %v, want one diagnostic with a fix", diagnostics)
+			}
+
+			got := applyFix(pass, tt.src, diagnostics[0].SuggestedFixes[0])
+
+			formatted, err := format.Source([]byte(got))
+			if err != nil {
+				t.Fatalf("fixed source doesn't parse: %v\n%s", err, got)
+			}
+			if string(formatted) != tt.want {
+				t.Errorf("fixed source =\n%s\nwant\n%s", formatted, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"unicode/utf8"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"github.com/hel1th/loglinter/pkg/secrets"
+	"golang.org/x/tools/go/analysis"
+)
+
+// SetSecretDetectors replaces the default detectors. An empty, non-nil list
+// turns secret value detection off.
+func (r *SensitiveDataRule) SetSecretDetectors(detectors []*secrets.Detector) {
+	r.secretDetectors = detectors
+}
+
+func (r *SensitiveDataRule) detectors() []*secrets.Detector {
+	if r.secretDetectors == nil {
+		r.secretDetectors = secrets.Detectors(secrets.Options{})
+	}
+
+	return r.secretDetectors
+}
+
+// checkSecrets reports secret values written into the text of the message
+// and into constant attribute values. It also returns the reported
+// expressions.
+func (r *SensitiveDataRule) checkSecrets(pass *analysis.Pass, logCall loggers.LogCall) ([]finding, []ast.Expr) {
+	var (
+		findings []finding
+		reported []ast.Expr
+	)
+
+	for _, seg := range logCall.Text.Segments {
+		if seg.Kind != loggers.LiteralSegment {
+			continue
+		}
+
+		for _, match := range secrets.Scan(seg.Text, r.detectors()) {
+			pos, end := matchSpan(seg, match)
+			findings = append(findings, r.secretFinding(pos, end, match))
+		}
+	}
+	if len(findings) > 0 {
+		reported = append(reported, logCall.Message)
+	}
+
+	var checkAttrs func(attrs []loggers.Attr)
+	checkAttrs = func(attrs []loggers.Attr) {
+		for _, attr := range attrs {
+			checkAttrs(attr.Group)
+
+			if attr.Value == nil {
+				continue
+			}
+
+			tv, ok := pass.TypesInfo.Types[attr.Value]
+			if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+				continue
+			}
+
+			for _, match := range secrets.Scan(constant.StringVal(tv.Value), r.detectors()) {
+				findings = append(findings, r.secretFinding(attr.Value.Pos(), attr.Value.End(), match))
+				reported = append(reported, attr.Value)
+			}
+		}
+	}
+	checkAttrs(logCall.Attrs)
+
+	return findings, reported
+}
+
+func (r *SensitiveDataRule) secretFinding(pos, end token.Pos, match secrets.Match) finding {
+	f := newFinding(analysis.Diagnostic{
+		Pos:      pos,
+		End:      end,
+		Message:  fmt.Sprintf("%s: hardcoded %s (%s)", r.Message(), match.Detector.Description, match.Detector.Name),
+		Category: r.Name(),
+	}, Category(match.Detector.Category), evidenceSecret)
+
+	// random-looking text may as well be an ID or a hash
+	if match.Detector.Heuristic {
+		f.confidence = 60
+	}
+	f.profiles = r.detectorProfiles(match.Detector.Name)
+
+	return f
+}
+
+// matchSpan returns the source span of a match in the text of a segment.
+func matchSpan(seg loggers.Segment, match secrets.Match) (token.Pos, token.Pos) {
+	var pos, end token.Pos
+
+	offset := 0
+	for _, r := range seg.Runes {
+		if offset == match.Start {
+			pos = r.Pos
+		}
+		offset += utf8.RuneLen(r.Value)
+		if offset == match.End {
+			end = r.End
+			break
+		}
+	}
+
+	// invalid UTF-8 from escapes can't be mapped back exactly
+	if !pos.IsValid() || !end.IsValid() {
+		return seg.Runes[0].Pos, seg.Runes[len(seg.Runes)-1].End
+	}
+
+	return pos, end
+}
@@ -0,0 +1,211 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/annotations"
+	"github.com/hel1th/loglinter/pkg/dictionaries"
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+// DefaultStructDepth is how many levels of nested structs are searched for
+// sensitive fields when no depth is configured.
+const DefaultStructDepth = 3
+
+// SensitiveStructRule reports values logged whole, like slog.Any("user", u)
+// or log.Printf("%+v", cfg), whose type has fields named like sensitive
+// data. Types that control their own log representation are not searched.
+type SensitiveStructRule struct {
+	// matches field names against the sensitive keywords
+	keys     SensitiveDataRule
+	maxDepth int
+}
+
+func (r *SensitiveStructRule) Name() string {
+	return "no-sensitive-structs"
+}
+
+func (r *SensitiveStructRule) Message() string {
+	return "logged value has sensitive fields"
+}
+
+func (r *SensitiveStructRule) SetCustomPatterns(patterns []string) {
+	r.keys.SetCustomPatterns(patterns)
+}
+
+func (r *SensitiveStructRule) SetBenignPhrases(phrases []string) {
+	r.keys.SetBenignPhrases(phrases)
+}
+
+func (r *SensitiveStructRule) SetLanguages(dicts []dictionaries.Dictionary) {
+	r.keys.SetLanguages(dicts)
+}
+
+func (r *SensitiveStructRule) SetMaxDepth(depth int) {
+	r.maxDepth = depth
+}
+
+func (r *SensitiveStructRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	for _, expr := range loggedValues(logCall) {
+		typ := pass.TypesInfo.TypeOf(expr)
+		if typ == nil {
+			continue
+		}
+
+		// values of annotated types are tainted and reported by
+		// no-sensitive-data
+		if _, ok := annotations.SensitiveType(pass, typ); ok {
+			continue
+		}
+		// and values of well-known types by no-sensitive-types
+		if _, ok := matchType(typ); ok {
+			continue
+		}
+
+		fields := r.sensitiveFields(pass, typ, types.ExprString(expr), 0, make(map[types.Type]bool))
+		if len(fields) == 0 {
+			continue
+		}
+
+		diagnostics = append(diagnostics, analysis.Diagnostic{
+			Pos:      expr.Pos(),
+			End:      expr.End(),
+			Message:  fmt.Sprintf("%s: %s", r.Message(), strings.Join(fields, ", ")),
+			Category: r.Name(),
+		})
+	}
+
+	return diagnostics
+}
+
+// loggedValues returns the expressions a log call prints: dynamic parts and
+// printf arguments of the message, extra arguments of print-style methods
+// and attribute values.
+func loggedValues(logCall loggers.LogCall) []ast.Expr {
+	var values []ast.Expr
+
+	for _, seg := range logCall.Text.Segments {
+		if seg.Expr == nil {
+			continue
+		}
+		// %T and %p don't print the value
+		if seg.Kind == loggers.VerbSegment && (strings.HasSuffix(seg.Text, "T") || strings.HasSuffix(seg.Text, "p")) {
+			continue
+		}
+		values = append(values, seg.Expr)
+	}
+
+	spec := logCall.Spec
+	if !spec.Printf && spec.AttrsIndex < 0 && spec.MessageIndex < len(logCall.Call.Args) {
+		values = append(values, logCall.Call.Args[spec.MessageIndex+1:]...)
+	}
+
+	var addAttrs func(attrs []loggers.Attr)
+	addAttrs = func(attrs []loggers.Attr) {
+		for _, attr := range attrs {
+			if attr.Value != nil {
+				values = append(values, attr.Value)
+			}
+			addAttrs(attr.Group)
+		}
+	}
+	addAttrs(logCall.Attrs)
+
+	return values
+}
+
+// sensitiveFields walks a type through pointers, slices, arrays and maps and
+// returns the paths of struct fields with sensitive names, nesting at most
+// maxDepth structs deep.
+func (r *SensitiveStructRule) sensitiveFields(pass *analysis.Pass, typ types.Type, path string, depth int, seen map[types.Type]bool) []string {
+	typ = types.Unalias(typ)
+
+	if seen[typ] || r.hasLogRepresentation(pass, typ) {
+		return nil
+	}
+
+	if reason, ok := annotations.SensitiveType(pass, typ); ok {
+		return []string{fmt.Sprintf("%s (%s)", path, reason)}
+	}
+	seen[typ] = true
+	defer delete(seen, typ)
+
+	switch t := typ.Underlying().(type) {
+	case *types.Pointer:
+		return r.sensitiveFields(pass, t.Elem(), path, depth, seen)
+	case *types.Slice:
+		return r.sensitiveFields(pass, t.Elem(), path+"[]", depth, seen)
+	case *types.Array:
+		return r.sensitiveFields(pass, t.Elem(), path+"[]", depth, seen)
+	case *types.Map:
+		return r.sensitiveFields(pass, t.Elem(), path+"[]", depth, seen)
+	case *types.Struct:
+		if depth >= r.depth() {
+			return nil
+		}
+
+		var found []string
+		for field := range t.Fields() {
+			fieldPath := path + "." + field.Name()
+			if reason, ok := annotations.Sensitive(pass, field); ok {
+				found = append(found, fmt.Sprintf("%s (%s)", fieldPath, reason))
+				continue
+			}
+			if len(r.keys.findSensitiveKeys(field.Name())) > 0 {
+				found = append(found, fieldPath)
+				continue
+			}
+			found = append(found, r.sensitiveFields(pass, field.Type(), fieldPath, depth+1, seen)...)
+		}
+		return found
+	}
+
+	return nil
+}
+
+func (r *SensitiveStructRule) depth() int {
+	if r.maxDepth <= 0 {
+		return DefaultStructDepth
+	}
+
+	return r.maxDepth
+}
+
+// hasLogRepresentation reports whether values of a type are logged through
+// their own method rather than field by field: slog.LogValuer,
+// zapcore.ObjectMarshaler, fmt.Stringer or error. Methods of the package
+// that expose sensitive fields, like a String method returning
+// fmt.Sprintf("%+v", *u), don't count.
+func (r *SensitiveStructRule) hasLogRepresentation(pass *analysis.Pass, typ types.Type) bool {
+	// a value doesn't have the methods of its pointer
+	methods := types.NewMethodSet(typ)
+	formatting := SensitiveMethodRule{keys: r.keys}
+
+	for _, method := range []struct {
+		name            string
+		params, results int
+	}{
+		{"LogValue", 0, 1},
+		{"MarshalLogObject", 1, 1},
+		{"String", 0, 1},
+		{"Error", 0, 1},
+	} {
+		sel := methods.Lookup(nil, method.name)
+		if sel == nil {
+			continue
+		}
+
+		sig := sel.Type().(*types.Signature)
+		if sig.Params().Len() == method.params && sig.Results().Len() == method.results {
+			return !formatting.exposes(pass, sel.Obj().(*types.Func))
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,178 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSensitiveStructRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		depth      int
+		wantFields []string
+	}{
+		{
+			name:       "printf verb",
+			body:       `log.Printf("user %+v", u)`,
+			wantFields: []string{"u.Password", "u.Profile.APIKey"},
+		},
+		{
+			name:       "pointer in slog any",
+			body:       `slog.Info("loaded", slog.Any("user", &u))`,
+			wantFields: []string{"&u.Password", "&u.Profile.APIKey"},
+		},
+		{
+			name:       "slice in zap any",
+			body:       `zap.L().Info("loaded", zap.Any("users", users))`,
+			wantFields: []string{"users[].Password", "users[].Profile.APIKey"},
+		},
+		{
+			name:       "map in zap reflect",
+			body:       `zap.L().Info("loaded", zap.Reflect("byName", byName))`,
+			wantFields: []string{"byName[].Password", "byName[].Profile.APIKey"},
+		},
+		{
+			name:       "sugared pairs",
+			body:       `zap.L().Sugar().Infow("loaded", "cfg", cfg)`,
+			wantFields: []string{"cfg.DB.Password"},
+		},
+		{
+			name:       "print arguments",
+			body:       `log.Print("user ", u.Profile)`,
+			wantFields: []string{"u.Profile.APIKey"},
+		},
+		{
+			name:       "depth limit",
+			body:       `log.Printf("user %+v", u)`,
+			depth:      1,
+			wantFields: []string{"u.Password"},
+		},
+		{
+			name:       "annotated fields",
+			body:       `slog.Info("paid", "card", c)`,
+			wantFields: []string{`c.Number (tag loglinter:"sensitive")`, "c.Expiry (//loglinter:sensitive)"},
+		},
+		{
+			name:       "annotated type",
+			body:       `log.Printf("order %v", o)`,
+			wantFields: []string{"o.Pin (//loglinter:sensitive)"},
+		},
+		{
+			name: "stringer",
+			body: `log.Printf("account %v", acc)`,
+		},
+		{
+			name: "log valuer",
+			body: `slog.Info("loaded", "session", sess)`,
+		},
+		{
+			name:       "pointer receiver not used for values",
+			body:       `log.Printf("token %v", tok)`,
+			wantFields: []string{"tok.Secret"},
+		},
+		{
+			name:       "stringer printing its fields",
+			body:       `log.Printf("login %v", &lg)`,
+			wantFields: []string{"&lg.Password"},
+		},
+		{
+			name: "type only",
+			body: `log.Printf("user %T at %p", u, &u)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &SensitiveStructRule{}
+			rule.SetMaxDepth(tt.depth)
+
+			src := `package test
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+type profile struct {
+	Name   string
+	APIKey string
+}
+
+type user struct {
+	Name     string
+	Password string
+	Profile  profile
+}
+
+type config struct {
+	DB struct {
+		Host     string
+		Password string
+	}
+}
+
+type card struct {
+	Holder string
+	Number string ` + "`" + `loglinter:"sensitive"` + "`" + `
+	Expiry string //loglinter:sensitive
+}
+
+//loglinter:sensitive
+type pin string
+
+type order struct {
+	ID  int
+	Pin pin
+}
+
+type account struct{ Password string }
+
+func (a account) String() string { return "account" }
+
+type session struct{ Token string }
+
+func (s session) LogValue() slog.Value { return slog.StringValue("session") }
+
+type token struct{ Secret string }
+
+func (t *token) String() string { return "token" }
+
+type login struct{ Password string }
+
+func (l *login) String() string { return fmt.Sprintf("%+v", *l) }
+
+var (
+	_ = log.Print
+	_ = slog.Info
+	_ = zap.L
+)
+
+func f(u user, users []user, byName map[string]*user, cfg config, acc account, sess session, tok token, c card, o order, lg login) {
+	` + tt.body + `
+}
+`
+			diagnostics := checkSource(t, rule, src)
+
+			if len(tt.wantFields) == 0 {
+				if len(diagnostics) != 0 {
+					t.Fatalf("Check() reported %q, want nothing", diagnostics[0].Message)
+				}
+				return
+			}
+
+			if len(diagnostics) != 1 {
+				t.Fatalf("Check() reported %d diagnostics, want 1", len(diagnostics))
+			}
+
+			_, list, _ := strings.Cut(diagnostics[0].Message, ": ")
+			if fields := strings.Split(list, ", "); !slices.Equal(fields, tt.wantFields) {
+				t.Errorf("Check() fields = %v, want %v", fields, tt.wantFields)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"github.com/hel1th/loglinter/pkg/annotations"
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"github.com/hel1th/loglinter/pkg/taint"
+	"golang.org/x/tools/go/analysis"
+)
+
+// DefaultTaintSources are functions whose results are treated as secrets
+// when no sources are configured.
+var DefaultTaintSources = []string{
+	`os.Getenv("*SECRET*")`,
+	`os.Getenv("*PASSWORD*")`,
+	`os.Getenv("*TOKEN*")`,
+	`os.Getenv("*API_KEY*")`,
+	`(net/http.Header).Get("Authorization")`,
+	`(net/http.Header).Get("Cookie")`,
+}
+
+func (r *SensitiveDataRule) SetTaintSources(sources []taint.Source) {
+	r.taintSources = sources
+}
+
+// IsSensitiveName reports whether an identifier, like userPassword, contains
+// a sensitive keyword.
+func (r *SensitiveDataRule) IsSensitiveName(name string) bool {
+	return len(r.findSensitiveKeys(name)) > 0
+}
+
+// weakKeywords are credential keywords too common in names of other things,
+// like authors or map keys, to tell a secret on their own.
+var weakKeywords = []string{"auth", "authorization", "key"}
+
+// IsCredentialName reports whether an identifier, like dbPassword, contains
+// a credential keyword other than a weak one. Unlike IsSensitiveName, it is
+// confident enough to infer that a function returning such a field returns
+// a secret.
+func (r *SensitiveDataRule) IsCredentialName(name string) bool {
+	for _, keyword := range r.findSensitiveKeys(name) {
+		if r.categoryOf(keyword) == CategoryCredential && !slices.Contains(weakKeywords, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// taintFlows runs the taint analysis once per package. It needs the result
+// of taint.SSAAnalyzer and is skipped without it.
+func (r *SensitiveDataRule) taintFlows(pass *analysis.Pass, call *ast.CallExpr) []taint.Flow {
+	if r.taintPass != pass {
+		r.taintPass = pass
+		r.taintResult = nil
+
+		if pkg, ok := pass.ResultOf[taint.SSAAnalyzer].(*taint.SSA); ok {
+			r.taintResult = taint.Analyze(pkg, pass.TypesInfo, taint.Config{
+				SensitiveName: r.IsSensitiveName,
+				SensitiveType: func(typ types.Type) bool {
+					_, ok := annotations.SensitiveType(pass, typ)
+					return ok || len(r.typeProfiles(typ)) > 0
+				},
+				SensitiveObject: func(obj types.Object) bool {
+					if fn, ok := obj.(*types.Func); ok {
+						if _, ok := annotations.SecretSource(pass, fn); ok {
+							return true
+						}
+					}
+					_, ok := annotations.Sensitive(pass, obj)
+					return ok
+				},
+				Sources: r.taintSources,
+			})
+		}
+	}
+
+	return r.taintResult.Flows(call)
+}
+
+// checkTaint reports sensitive values flowing into the message or attributes
+// of a log call. Arguments overlapping ones already reported are skipped.
+func (r *SensitiveDataRule) checkTaint(pass *analysis.Pass, logCall loggers.LogCall, reported []ast.Expr) []finding {
+	var findings []finding
+
+	for _, flow := range r.taintFlows(pass, logCall.Call) {
+		if flow.Arg >= len(logCall.Call.Args) {
+			continue
+		}
+
+		arg := logCall.Call.Args[flow.Arg]
+		typ := pass.TypesInfo.TypeOf(arg)
+		if overlapsExpr(reported, arg) || !mayHoldSecret(typ) && !isNumber(typ) {
+			continue
+		}
+
+		sink := sinkName(logCall, flow.Arg)
+		if sink == "" {
+			continue
+		}
+
+		// sources named after a keyword tell the category, configured
+		// sources and annotations are taken for credentials
+		category := CategoryCredential
+		keywords := r.matchKeywords(flow.Source())
+		if !mayHoldSecret(typ) {
+			keywords = r.financialKeywords(keywords)
+			if len(keywords) == 0 {
+				continue
+			}
+		}
+		if len(keywords) > 0 {
+			category = r.categoryOf(keywords[0])
+		}
+
+		f := newFinding(analysis.Diagnostic{
+			Pos:      arg.Pos(),
+			End:      arg.End(),
+			Message:  fmt.Sprintf("%s: %s flows into the %s (%s -> %s)", r.Message(), flow.Source(), sink, flow, sink),
+			Category: r.Name(),
+		}, category, evidenceTainted)
+
+		f.profiles = r.keywordProfiles(keywords)
+		for _, profile := range r.typeProfiles(pass.TypesInfo.TypeOf(arg)) {
+			if !slices.Contains(f.profiles, profile) {
+				f.profiles = append(f.profiles, profile)
+			}
+		}
+		// values of regulated types, like net.IP, are of their profile's category
+		if len(keywords) == 0 && len(f.profiles) > 0 {
+			f.category = r.profileCategoryOf(f.profiles[0])
+		}
+
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// sinkName names the part of a log call an argument is.
+func sinkName(logCall loggers.LogCall, arg int) string {
+	spec := logCall.Spec
+
+	switch {
+	case arg == spec.MessageIndex:
+		return "message"
+	case spec.Printf && arg > spec.MessageIndex:
+		return "format arguments"
+	case spec.AttrsIndex >= 0 && arg >= spec.AttrsIndex:
+		return "attributes"
+	case arg > spec.MessageIndex:
+		// print-style methods concatenate every argument
+		return "message"
+	}
+
+	return ""
+}
+
+// overlapsExpr reports whether expr is within one of exprs or contains one,
+// like "user: " + userPassword contains userPassword.
+func overlapsExpr(exprs []ast.Expr, expr ast.Expr) bool {
+	for _, e := range exprs {
+		if e.Pos() < expr.End() && expr.Pos() < e.End() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsExpr(exprs []ast.Expr, expr ast.Expr) bool {
+	for _, e := range exprs {
+		if e.Pos() <= expr.Pos() && expr.End() <= e.End() {
+			return true
+		}
+	}
+
+	return false
+}
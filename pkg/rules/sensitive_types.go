@@ -0,0 +1,270 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+)
+
+// knownSensitive is a type, or a field or method of one, whose values leak
+// sensitive data when logged.
+type knownSensitive struct {
+	// qualified type name, like net/http.Request
+	typ string
+	// field or method of the type, empty for values of the type itself
+	member string
+	// constant first arguments that make a method call sensitive, like the
+	// Authorization of Header.Get, compared case insensitively; any call
+	// is sensitive when empty
+	args   []string
+	reason string
+	// selector that logs safely instead, appended to the value or to the
+	// receiver of the member, like .URL.Path
+	alternative string
+}
+
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token"}
+
+// knownSensitives is the database of well-known sensitive types and members
+// of the standard library and common third-party packages.
+var knownSensitives = []knownSensitive{
+	{typ: "net/http.Request", reason: "headers, cookies and query parameters may carry credentials", alternative: ".URL.Path"},
+	{typ: "net/http.Request", member: "RequestURI", reason: "the query string may carry tokens", alternative: ".URL.Path"},
+	{typ: "net/http.Response", reason: "headers may carry cookies and tokens", alternative: ".Status"},
+	{typ: "net/http.Header", reason: "Authorization and Cookie headers carry credentials"},
+	{typ: "net/http.Header", member: "Get", args: credentialHeaders, reason: "the header carries credentials"},
+	{typ: "net/http.Header", member: "Values", args: credentialHeaders, reason: "the header carries credentials"},
+	{typ: "net/http.Cookie", reason: "cookie values are often session tokens", alternative: ".Name"},
+	{typ: "net/http.Request", member: "Cookie", reason: "cookie values are often session tokens"},
+
+	{typ: "net/url.URL", reason: "the query string and user info may carry credentials", alternative: ".Path"},
+	{typ: "net/url.URL", member: "String", reason: "the query string and user info may carry credentials", alternative: ".Path"},
+	{typ: "net/url.URL", member: "RequestURI", reason: "the query string may carry tokens", alternative: ".Path"},
+	{typ: "net/url.URL", member: "RawQuery", reason: "the query string may carry tokens"},
+	{typ: "net/url.Values", reason: "query and form parameters may carry tokens"},
+	{typ: "net/url.Userinfo", reason: "user info holds a password", alternative: ".Username()"},
+	{typ: "net/url.Userinfo", member: "String", reason: "user info holds a password", alternative: ".Username()"},
+	{typ: "net/url.Userinfo", member: "Password", reason: "user info holds a password"},
+
+	{typ: "crypto/tls.Certificate", reason: "the certificate holds its private key"},
+	{typ: "crypto/tls.Config", reason: "the configuration holds certificates with private keys"},
+	{typ: "crypto/rsa.PrivateKey", reason: "private key"},
+	{typ: "crypto/ecdsa.PrivateKey", reason: "private key"},
+	{typ: "crypto/ed25519.PrivateKey", reason: "private key"},
+
+	{typ: "database/sql.DB", reason: "its connector holds the data source name with the password", alternative: ".Stats()"},
+
+	{typ: "github.com/go-sql-driver/mysql.Config", reason: "the configuration holds the database password"},
+	{typ: "github.com/jackc/pgx/v5.ConnConfig", reason: "the configuration holds the database password"},
+	{typ: "github.com/jackc/pgx/v5/pgconn.Config", reason: "the configuration holds the database password"},
+	{typ: "github.com/redis/go-redis/v9.Options", reason: "the options hold the Redis password"},
+	{typ: "golang.org/x/oauth2.Token", reason: "access and refresh tokens"},
+	{typ: "golang.org/x/oauth2.Config", reason: "the configuration holds the client secret"},
+}
+
+// SensitiveTypeRule reports logged values of well-known sensitive types, like
+// *http.Request or tls.Certificate, and sensitive members of them, like
+// r.URL.String() or r.Header.Get("Authorization"), suggesting a safe
+// alternative where there is one.
+type SensitiveTypeRule struct{}
+
+func (r *SensitiveTypeRule) Name() string {
+	return "no-sensitive-types"
+}
+
+func (r *SensitiveTypeRule) Message() string {
+	return "logged value may leak sensitive data"
+}
+
+func (r *SensitiveTypeRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	params := attrParams(logCall.Attrs)
+
+	for _, expr := range loggedValues(logCall) {
+		known, base, ok := matchMember(pass, expr)
+		if !ok {
+			typ := pass.TypesInfo.TypeOf(expr)
+			known, ok = matchType(typ)
+			base = expr
+			// the alternative selects from a value, not from its elements
+			if qualifiedName(typ) != known.typ {
+				known.alternative = ""
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		diag := analysis.Diagnostic{
+			Pos:      expr.Pos(),
+			End:      expr.End(),
+			Message:  fmt.Sprintf("%s: %s (%s)", r.Message(), types.ExprString(expr), known.reason),
+			Category: r.Name(),
+		}
+
+		if known.alternative != "" && fits(pass.TypesInfo.TypeOf(base), known.alternative, params[expr]) {
+			alternative := types.ExprString(base) + known.alternative
+			diag.Message += fmt.Sprintf(", log %s instead", alternative)
+			diag.SuggestedFixes = []analysis.SuggestedFix{
+				{
+					Message: "Log " + alternative + " instead",
+					TextEdits: []analysis.TextEdit{
+						{Pos: expr.Pos(), End: expr.End(), NewText: []byte(alternative)},
+					},
+				},
+			}
+		}
+
+		diagnostics = append(diagnostics, diag)
+	}
+
+	return diagnostics
+}
+
+// fits reports whether an alternative selected from a value of typ can be
+// passed where the logged value is, to a parameter of type param if it's
+// an attribute value.
+func fits(typ types.Type, alternative string, param types.Type) bool {
+	altType := selectorType(typ, alternative)
+	return altType != nil && (param == nil || types.AssignableTo(altType, param))
+}
+
+// matchMember matches a field selection or method call against the
+// database. It also returns the receiver the member is selected from.
+func matchMember(pass *analysis.Pass, expr ast.Expr) (knownSensitive, ast.Expr, bool) {
+	expr = ast.Unparen(expr)
+
+	var args []ast.Expr
+	if call, ok := expr.(*ast.CallExpr); ok {
+		expr, args = ast.Unparen(call.Fun), call.Args
+	}
+
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return knownSensitive{}, nil, false
+	}
+
+	selection, ok := pass.TypesInfo.Selections[sel]
+	if !ok {
+		return knownSensitive{}, nil, false
+	}
+
+	recv := qualifiedName(selection.Recv())
+	for _, known := range knownSensitives {
+		if known.typ != recv || known.member != sel.Sel.Name {
+			continue
+		}
+		if len(known.args) > 0 && (len(args) == 0 || !constantIn(pass, args[0], known.args)) {
+			continue
+		}
+		return known, sel.X, true
+	}
+
+	return knownSensitive{}, nil, false
+}
+
+// matchType matches a type, through pointers, slices and arrays, against the
+// database of sensitive types.
+func matchType(typ types.Type) (knownSensitive, bool) {
+	for typ != nil {
+		switch t := types.Unalias(typ).(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+			continue
+		case *types.Slice:
+			typ = t.Elem()
+			continue
+		case *types.Array:
+			typ = t.Elem()
+			continue
+		}
+
+		name := qualifiedName(typ)
+		for _, known := range knownSensitives {
+			if known.typ == name && known.member == "" {
+				return known, true
+			}
+		}
+		return knownSensitive{}, false
+	}
+
+	return knownSensitive{}, false
+}
+
+// qualifiedName returns the package path and name of a named type, or of
+// the type a pointer points to, like net/http.Request.
+func qualifiedName(typ types.Type) string {
+	typ = types.Unalias(typ)
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = types.Unalias(ptr.Elem())
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
+
+func constantIn(pass *analysis.Pass, expr ast.Expr, values []string) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return false
+	}
+
+	s := constant.StringVal(tv.Value)
+	return slices.ContainsFunc(values, func(v string) bool { return strings.EqualFold(s, v) })
+}
+
+// attrParams returns the types of the value parameters of the attribute
+// constructors logged values are passed to, like string for slog.String.
+func attrParams(attrs []loggers.Attr) map[ast.Expr]types.Type {
+	params := make(map[ast.Expr]types.Type)
+
+	var add func(attrs []loggers.Attr)
+	add = func(attrs []loggers.Attr) {
+		for _, attr := range attrs {
+			if attr.Value != nil && attr.Func != nil {
+				if sig := attr.Func.Type().(*types.Signature); sig.Params().Len() >= 2 {
+					params[attr.Value] = sig.Params().At(1).Type()
+				}
+			}
+			add(attr.Group)
+		}
+	}
+	add(attrs)
+
+	return params
+}
+
+// selectorType returns the type of a selector like .URL.Path or .Stats()
+// applied to a value of typ, or nil if it doesn't apply.
+func selectorType(typ types.Type, selector string) types.Type {
+	for _, name := range strings.Split(strings.TrimPrefix(selector, "."), ".") {
+		name, call := strings.CutSuffix(name, "()")
+
+		obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, name)
+		switch obj := obj.(type) {
+		case *types.Var:
+			typ = obj.Type()
+		case *types.Func:
+			results := obj.Type().(*types.Signature).Results()
+			if !call || results.Len() != 1 {
+				return nil
+			}
+			typ = results.At(0).Type()
+		default:
+			return nil
+		}
+	}
+
+	return typ
+}
@@ -0,0 +1,131 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSensitiveTypeRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		wantFix []string
+	}{
+		{
+			name:    "request",
+			body:    `log.Printf("got %v", r)`,
+			want:    []string{"r (headers, cookies and query parameters may carry credentials), log r.URL.Path instead"},
+			wantFix: []string{"r.URL.Path"},
+		},
+		{
+			name:    "url string",
+			body:    `slog.Info("got request", "url", r.URL.String())`,
+			want:    []string{"r.URL.String() (the query string and user info may carry credentials), log r.URL.Path instead"},
+			wantFix: []string{"r.URL.Path"},
+		},
+		{
+			name:    "url",
+			body:    `log.Print("calling ", u)`,
+			want:    []string{"u (the query string and user info may carry credentials), log u.Path instead"},
+			wantFix: []string{"u.Path"},
+		},
+		{
+			name: "header",
+			body: `slog.Info("got request", slog.Any("headers", r.Header))`,
+			want: []string{"r.Header (Authorization and Cookie headers carry credentials)"},
+		},
+		{
+			name: "credential header",
+			body: `slog.Info("got request", "auth", r.Header.Get("authorization"), "type", r.Header.Get("Content-Type"))`,
+			want: []string{`r.Header.Get("authorization") (the header carries credentials)`},
+		},
+		{
+			name: "cookies",
+			body: `log.Printf("cookies %v", r.Cookies())`,
+			want: []string{"r.Cookies() (cookie values are often session tokens)"},
+		},
+		{
+			name:    "user info",
+			body:    `log.Printf("user %s", u.User)`,
+			want:    []string{"u.User (user info holds a password), log u.User.Username() instead"},
+			wantFix: []string{"u.User.Username()"},
+		},
+		{
+			name:    "typed attribute",
+			body:    `slog.Info("calling", slog.String("url", u.String()))`,
+			want:    []string{"u.String() (the query string and user info may carry credentials), log u.Path instead"},
+			wantFix: []string{"u.Path"},
+		},
+		{
+			name: "typed attribute of another type",
+			body: `zap.L().Info("calling", zap.Stringer("url", u))`,
+			want: []string{"u (the query string and user info may carry credentials)"},
+		},
+		{
+			name: "certificate",
+			body: `slog.Info("loaded", "cert", cert)`,
+			want: []string{"cert (the certificate holds its private key)"},
+		},
+		{
+			name:    "database",
+			body:    `log.Printf("connected %+v", db)`,
+			want:    []string{"db (its connector holds the data source name with the password), log db.Stats() instead"},
+			wantFix: []string{"db.Stats()"},
+		},
+		{
+			name: "safe members",
+			body: `slog.Info("got request", "method", r.Method, "path", r.URL.Path, "host", u.Host)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := `package test
+
+import (
+	"crypto/tls"
+	"database/sql"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"go.uber.org/zap"
+)
+
+var (
+	_ = log.Print
+	_ = slog.Info
+	_ = zap.L
+)
+
+func f(r *http.Request, u *url.URL, cert tls.Certificate, db *sql.DB) {
+	` + tt.body + `
+}
+`
+			diagnostics := checkSource(t, &SensitiveTypeRule{}, src)
+
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("Check() reported %d diagnostics, want %d", len(diagnostics), len(tt.want))
+			}
+			for i, diag := range diagnostics {
+				if !strings.HasSuffix(diag.Message, tt.want[i]) {
+					t.Errorf("Check() message = %q, want suffix %q", diag.Message, tt.want[i])
+				}
+
+				var fix string
+				if len(diag.SuggestedFixes) > 0 {
+					fix = string(diag.SuggestedFixes[0].TextEdits[0].NewText)
+				}
+				var wantFix string
+				if i < len(tt.wantFix) {
+					wantFix = tt.wantFix[i]
+				}
+				if fix != wantFix {
+					t.Errorf("Check() fix = %q, want %q", fix, wantFix)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/tools/go/analysis"
+)
+
+// DefaultBenignPhrases are phrases that contain a sensitive keyword without
+// exposing any sensitive data.
+var DefaultBenignPhrases = []string{
+	"token bucket",
+	"auth service",
+	"auth middleware",
+	"secret manager client",
+	"secret manager",
+	"password policy",
+}
+
+func (r *SensitiveDataRule) SetBenignPhrases(phrases []string) {
+	r.benignPhrases = phrases
+}
+
+func (r *SensitiveDataRule) benign() []string {
+	return append(slices.Clone(DefaultBenignPhrases), r.benignPhrases...)
+}
+
+// splitWords splits text or an identifier into case folded words at
+// separators, camelCase humps and the end of acronyms, so that userPassword,
+// user_password and "user password" all give user and password, and
+// HTTPAuthToken gives http, auth and token. Text is NFKC normalized first,
+// so fullwidth and other compatibility forms give the same words.
+func splitWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+		caser = cases.Fold()
+	)
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, foldWord(caser, string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(norm.NFKC.String(s))
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		if i > 0 && len(word) > 0 {
+			prev := runes[i-1]
+			switch {
+			case unicode.IsUpper(r) && unicode.IsLower(prev):
+				// userPassword
+				flush()
+			case unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+				// HTTPAuth: the last capital starts a new word
+				flush()
+			case unicode.IsDigit(r) != unicode.IsDigit(prev):
+				// oauth2token
+				flush()
+			}
+		}
+
+		word = append(word, r)
+	}
+	flush()
+
+	return words
+}
+
+// matchKeywords returns the keywords occurring as whole words in text. A
+// keyword of several words, like api_key, matches those words in sequence,
+// and keywords spelled differently, like api_key and api-key, are reported
+// once. Occurrences inside a benign phrase don't count.
+func (r *SensitiveDataRule) matchKeywords(text string) []string {
+	words := splitWords(text)
+
+	var benign [][2]int
+	for _, phrase := range r.benign() {
+		phraseWords := splitWords(phrase)
+		for _, start := range indexWords(words, phraseWords) {
+			benign = append(benign, [2]int{start, start + len(phraseWords)})
+		}
+	}
+
+	var found []string
+	seen := make(map[string]bool)
+
+	for _, keyword := range r.keywords() {
+		keywordWords := splitWords(keyword)
+		compact := strings.Join(keywordWords, "")
+		if len(keywordWords) == 0 || seen[compact] {
+			continue
+		}
+
+		for _, start := range indexWords(words, keywordWords) {
+			end := start + len(keywordWords)
+			inBenign := slices.ContainsFunc(benign, func(span [2]int) bool {
+				return span[0] <= start && end <= span[1]
+			})
+			if !inBenign {
+				found = append(found, keyword)
+				seen[compact] = true
+				break
+			}
+		}
+	}
+
+	return found
+}
+
+// indexWords returns every position at which sub occurs in words.
+func indexWords(words, sub []string) []int {
+	var starts []int
+
+	for i := 0; i+len(sub) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(sub)], sub) {
+			starts = append(starts, i)
+		}
+	}
+
+	return starts
+}
+
+// checkIdents reports dynamic parts of a message, like the userPassword of
+// "user: " + userPassword or the authToken argument of a %s verb, whose
+// names contain sensitive keywords. Expressions within reported ones are
+// skipped. It also returns the reported expressions.
+func (r *SensitiveDataRule) checkIdents(pass *analysis.Pass, logCall loggers.LogCall, reported []ast.Expr) ([]finding, []ast.Expr) {
+	var (
+		findings []finding
+		exprs    []ast.Expr
+	)
+
+	var values []ast.Expr
+	for _, seg := range logCall.Text.Segments {
+		if seg.Expr != nil && seg.Kind != loggers.LiteralSegment {
+			values = append(values, seg.Expr)
+		}
+	}
+
+	// print-style methods print every argument
+	spec := logCall.Spec
+	if !spec.Printf && spec.AttrsIndex < 0 && spec.MessageIndex < len(logCall.Call.Args) {
+		values = append(values, logCall.Call.Args[spec.MessageIndex+1:]...)
+	}
+
+	for _, expr := range values {
+		typ := pass.TypesInfo.TypeOf(expr)
+		if containsExpr(reported, expr) || !mayHoldSecret(typ) && !isNumber(typ) {
+			continue
+		}
+
+		name := valueName(pass, expr)
+		if name == "" {
+			continue
+		}
+
+		keywords := r.matchKeywords(name)
+		if !mayHoldSecret(typ) {
+			keywords = r.financialKeywords(keywords)
+		}
+		if len(keywords) == 0 {
+			continue
+		}
+
+		f := newFinding(analysis.Diagnostic{
+			Pos:      expr.Pos(),
+			End:      expr.End(),
+			Message:  fmt.Sprintf("%s: %s (%s)", r.Message(), types.ExprString(expr), strings.Join(keywords, ", ")),
+			Category: r.Name(),
+		}, r.categoryOf(keywords[0]), evidenceNamedValue)
+		f.profiles = r.keywordProfiles(keywords)
+		findings = append(findings, f)
+		exprs = append(exprs, expr)
+	}
+
+	return findings, exprs
+}
+
+// valueName returns the name a value is known by: a variable, the last
+// field of a selector or the called function, like GetPassword of
+// user.GetPassword(). Conversions are named by their operand.
+func valueName(pass *analysis.Pass, expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.StarExpr:
+		return valueName(pass, e.X)
+	case *ast.IndexExpr:
+		return valueName(pass, e.X)
+	case *ast.CallExpr:
+		if tv, ok := pass.TypesInfo.Types[e.Fun]; ok && tv.IsType() && len(e.Args) == 1 {
+			return valueName(pass, e.Args[0])
+		}
+		return valueName(pass, e.Fun)
+	}
+
+	return ""
+}
+
+// mayHoldSecret reports whether values of a type can carry sensitive data.
+// Flags and numbers named after secrets, like hasToken or tokenTTL, can't.
+func mayHoldSecret(typ types.Type) bool {
+	if typ == nil {
+		return false
+	}
+
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return true
+	}
+
+	return basic.Info()&(types.IsBoolean|types.IsNumeric) == 0
+}
+
+// isNumber reports whether typ is a numeric type.
+func isNumber(typ types.Type) bool {
+	if typ == nil {
+		return false
+	}
+
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsNumeric != 0
+}
+
+// financialKeywords keeps the keywords of the financial category. Card and
+// account numbers are often held in numbers, unlike other secrets, so
+// cardNumber is sensitive as an int64 while tokenTTL isn't.
+func (r *SensitiveDataRule) financialKeywords(keywords []string) []string {
+	return slices.DeleteFunc(slices.Clone(keywords), func(keyword string) bool {
+		return r.categoryOf(keyword) != CategoryFinancial
+	})
+}
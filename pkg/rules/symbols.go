@@ -3,6 +3,7 @@ package rules
 import (
 	"fmt"
 	"go/ast"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -22,36 +23,40 @@ func (r *NoSpecialSymbolsRule) Message() string {
 }
 
 func (r *NoSpecialSymbolsRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
-	message, ok := loggers.ExtractStringLit(logCall.Message)
-	if !ok {
+	message := logCall.Text.Literal()
+	if message == "" {
 		return nil
 	}
 
 	invalidChars := r.findInvalidCharacters(message)
 
 	if len(invalidChars) > 0 {
-		cleanedMessage := r.CleanMessage(message)
-
-		return []analysis.Diagnostic{
-			{
-				Pos:      logCall.Message.Pos(),
-				End:      logCall.Message.End(),
-				Message:  r.formatDiagnosticMessage(invalidChars),
-				Category: r.Name(),
-				SuggestedFixes: []analysis.SuggestedFix{
+		diag := analysis.Diagnostic{
+			Pos:      logCall.Message.Pos(),
+			End:      logCall.Message.End(),
+			Message:  r.formatDiagnosticMessage(invalidChars),
+			Category: r.Name(),
+		}
+
+		// only a plain literal can be rewritten as a whole
+		if lit, ok := logCall.Message.(*ast.BasicLit); ok {
+			if text, ok := loggers.ExtractStringLit(lit); ok {
+				diag.SuggestedFixes = []analysis.SuggestedFix{
 					{
 						Message: "Remove special symbols",
 						TextEdits: []analysis.TextEdit{
 							{
-								Pos:     logCall.Message.Pos(),
-								End:     logCall.Message.End(),
-								NewText: []byte(fmt.Sprintf(`"%s"`, cleanedMessage)),
+								Pos:     lit.Pos(),
+								End:     lit.End(),
+								NewText: []byte(strconv.Quote(r.CleanMessage(text))),
 							},
 						},
 					},
-				},
-			},
+				}
+			}
 		}
+
+		return []analysis.Diagnostic{diag}
 	}
 
 	return nil
@@ -0,0 +1,283 @@
+package rules
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"slices"
+
+	"github.com/hel1th/loglinter/pkg/loggers"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// typedConstructors are the attribute constructors of slog and zap, and the
+// field methods of zerolog events, by the type of value they take. slog has
+// no constructor for errors, Any keeps them as errors.
+var typedConstructors = map[string]map[string]string{
+	"log/slog": {
+		"string": "String", "int": "Int", "int64": "Int64", "uint64": "Uint64", "float64": "Float64", "bool": "Bool",
+		"time.Duration": "Duration", "time.Time": "Time", "error": "Any",
+	},
+	"go.uber.org/zap": {
+		"string": "String", "int": "Int", "int64": "Int64", "uint64": "Uint64", "float64": "Float64", "bool": "Bool",
+		"time.Duration": "Duration", "time.Time": "Time", "error": "NamedError",
+	},
+	"github.com/rs/zerolog": {
+		"string": "Str", "int": "Int", "int64": "Int64", "uint64": "Uint64", "float64": "Float64", "bool": "Bool",
+		"time.Duration": "Dur", "time.Time": "Time", "error": "AnErr",
+	},
+}
+
+// genericConstructors take values of any type.
+var genericConstructors = map[string][]string{
+	"log/slog":              {"Any"},
+	"go.uber.org/zap":       {"Any", "Reflect"},
+	"github.com/rs/zerolog": {"Interface", "Any"},
+}
+
+// errorConstructors log an error under the "error" key without taking one.
+var errorConstructors = map[string]string{
+	"go.uber.org/zap":       "Error",
+	"github.com/rs/zerolog": "Err",
+}
+
+// TypedAttrsRule reports attributes that lose the type of their value:
+// values turned into strings for a string constructor, like
+// slog.String("count", strconv.Itoa(n)) or zap.String("latency",
+// d.String()), and values of types with a dedicated constructor passed to
+// Any or Reflect, like zap.Any("ok", true). Encoders then can't render them
+// natively, and the conversion allocates. It suggests the constructor for
+// the type, like slog.Int or zap.Bool.
+type TypedAttrsRule struct{}
+
+func (r *TypedAttrsRule) Name() string {
+	return "typed-attrs"
+}
+
+func (r *TypedAttrsRule) Message() string {
+	return "attribute value loses its type"
+}
+
+func (r *TypedAttrsRule) Check(pass *analysis.Pass, logCall loggers.LogCall) []analysis.Diagnostic {
+	return r.checkAttrs(pass, logCall.Attrs)
+}
+
+// CheckPackage checks the attributes added by With calls.
+func (r *TypedAttrsRule) CheckPackage(pass *analysis.Pass) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	for _, withCall := range detectWithCalls(pass) {
+		diagnostics = append(diagnostics, r.checkAttrs(pass, withCall.Attrs)...)
+	}
+
+	return diagnostics
+}
+
+func (r *TypedAttrsRule) checkAttrs(pass *analysis.Pass, attrs []loggers.Attr) []analysis.Diagnostic {
+	var diagnostics []analysis.Diagnostic
+
+	walkAttrs(attrs, "", func(attr loggers.Attr, _ string) {
+		if attr.Func == nil || attr.Func.Pkg() == nil || attr.Value == nil || attr.KeyExpr == nil {
+			return
+		}
+
+		pkg := attr.Func.Pkg().Path()
+		constructors, ok := typedConstructors[pkg]
+		if !ok {
+			return
+		}
+
+		call, ok := ast.Unparen(attr.Expr).(*ast.CallExpr)
+		if !ok {
+			return
+		}
+
+		var (
+			message string
+			// replaces the value when it is stringified
+			value ast.Expr
+			typ   string
+		)
+
+		switch {
+		case attr.Func.Name() == constructors["string"]:
+			value, typ = stringified(pass, attr.Value)
+			if value == nil {
+				return
+			}
+			message = fmt.Sprintf("%s converts %s to a string", types.ExprString(attr.Value), types.ExprString(value))
+		case slices.Contains(genericConstructors[pkg], attr.Func.Name()):
+			typ = typeName(pass.TypesInfo.TypeOf(attr.Value))
+			if typ == "" || constructors[typ] == attr.Func.Name() {
+				return
+			}
+			message = fmt.Sprintf("%s hides the %s type of %s", constructorName(pass, call, attr.Func.Name()), typ, types.ExprString(attr.Value))
+		default:
+			return
+		}
+
+		name := constructors[typ]
+		if name == "" {
+			return
+		}
+
+		// errors logged under the default key don't need one
+		dropKey := typ == "error" && attr.Key == "error" && errorConstructors[pkg] != ""
+		if dropKey {
+			name = errorConstructors[pkg]
+		}
+
+		diag := analysis.Diagnostic{
+			Pos:      attr.Expr.Pos(),
+			End:      attr.Expr.End(),
+			Message:  fmt.Sprintf("%s: %s, use %s", r.Message(), message, constructorName(pass, call, name)),
+			Category: r.Name(),
+		}
+
+		if ident := funcIdent(call); ident != nil {
+			edits := []analysis.TextEdit{{Pos: ident.Pos(), End: ident.End(), NewText: []byte(name)}}
+
+			// the value keeps its source text, only what stringifies it and
+			// the dropped key are deleted
+			start := attr.Value.Pos()
+			if dropKey {
+				start = attr.KeyExpr.Pos()
+			}
+			if value == nil {
+				value = attr.Value
+			}
+			if start != value.Pos() {
+				edits = append(edits, analysis.TextEdit{Pos: start, End: value.Pos()})
+			}
+			if value.End() != attr.Value.End() {
+				edits = append(edits, analysis.TextEdit{Pos: value.End(), End: attr.Value.End()})
+			}
+
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Use %s", constructorName(pass, call, name)),
+				TextEdits: edits,
+			}}
+		}
+
+		diagnostics = append(diagnostics, diag)
+	})
+
+	return diagnostics
+}
+
+// stringified returns the value a string is made of and its type name, like
+// n and int for strconv.Itoa(n) or d and time.Duration for d.String(), or
+// nil if expr isn't a conversion of a value with a typed constructor.
+func stringified(pass *analysis.Pass, expr ast.Expr) (ast.Expr, string) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || call.Ellipsis.IsValid() {
+		return nil, ""
+	}
+
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok {
+		return nil, ""
+	}
+
+	var value ast.Expr
+
+	switch fn.FullName() {
+	case "strconv.Itoa", "strconv.FormatBool":
+		value = call.Args[0]
+	case "strconv.FormatInt", "strconv.FormatUint":
+		// other bases aren't decimal numbers
+		if tv := pass.TypesInfo.Types[call.Args[1]]; tv.Value == nil || tv.Value.Kind() != constant.Int || tv.Value.ExactString() != "10" {
+			return nil, ""
+		}
+		value = call.Args[0]
+	case "fmt.Sprint":
+		if len(call.Args) != 1 {
+			return nil, ""
+		}
+		value = call.Args[0]
+	case "fmt.Sprintf":
+		if len(call.Args) != 2 {
+			return nil, ""
+		}
+		if format, ok := constString(pass, call.Args[0]); !ok || (format != "%v" && format != "%d" && format != "%t") {
+			return nil, ""
+		}
+		value = call.Args[1]
+	case "(time.Duration).String", "(time.Time).String":
+		// Format is left alone, its layout may drop the time or the zone
+		value = ast.Unparen(call.Fun).(*ast.SelectorExpr).X
+	default:
+		// err.Error()
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok || fn.Name() != "Error" || len(call.Args) != 0 {
+			return nil, ""
+		}
+		if typ := pass.TypesInfo.TypeOf(sel.X); typ == nil || !types.Implements(typ, errorType) {
+			return nil, ""
+		}
+		return sel.X, "error"
+	}
+
+	typ := typeName(pass.TypesInfo.TypeOf(value))
+	if typ == "" || typ == "string" {
+		return nil, ""
+	}
+
+	return value, typ
+}
+
+// typeName returns the key of a type in typedConstructors, or "" if no
+// constructor takes values of the type without a conversion.
+func typeName(typ types.Type) string {
+	if typ == nil {
+		return ""
+	}
+
+	if types.Identical(typ, types.Universe.Lookup("error").Type()) {
+		return "error"
+	}
+
+	if named, ok := types.Unalias(typ).(*types.Named); ok {
+		switch name := qualifiedName(named); name {
+		case "time.Duration", "time.Time":
+			return name
+		}
+		return ""
+	}
+
+	for _, kind := range []types.BasicKind{types.String, types.Int, types.Int64, types.Uint64, types.Float64, types.Bool} {
+		if types.Identical(typ, types.Typ[kind]) {
+			return types.Typ[kind].Name()
+		}
+	}
+
+	return ""
+}
+
+// constructorName qualifies the name of a constructor like the call does,
+// like zap.Bool for a call of zap.Any. Field methods are left unqualified.
+func constructorName(pass *analysis.Pass, call *ast.CallExpr, name string) string {
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok {
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			if _, ok := pass.TypesInfo.Uses[ident].(*types.PkgName); ok {
+				return ident.Name + "." + name
+			}
+		}
+	}
+
+	return name
+}
+
+// funcIdent returns the identifier naming the function of a call.
+func funcIdent(call *ast.CallExpr) *ast.Ident {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		return fun
+	case *ast.SelectorExpr:
+		return fun.Sel
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package rules
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const typedAttrsSrc = `package test
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+)
+
+var (
+	_ = fmt.Sprint
+	_ = slog.Info
+	_ = strconv.Itoa
+	_ = zap.L
+)
+
+type level int
+
+func f(logger *zap.Logger, zl zerolog.Logger, n int, id int64, ok bool, lvl level, d time.Duration, t time.Time, err error) {
+	BODY
+}
+`
+
+func TestTypedAttrsRule(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "strconv",
+			body: `slog.Info("done", slog.String("count", strconv.Itoa(n)), slog.String("id", strconv.FormatInt(id, 10)), slog.String("ok", strconv.FormatBool(ok)))`,
+			want: []string{
+				`strconv.Itoa(n) converts n to a string, use slog.Int`,
+				`strconv.FormatInt(id, 10) converts id to a string, use slog.Int64`,
+				`strconv.FormatBool(ok) converts ok to a string, use slog.Bool`,
+			},
+		},
+		{
+			name: "other bases",
+			body: `slog.Info("done", slog.String("id", strconv.FormatInt(id, 16)))`,
+		},
+		{
+			name: "String and Error methods",
+			body: `logger.Info("done", zap.String("latency", d.String()), zap.String("at", t.String()), zap.String("reason", err.Error()))`,
+			want: []string{
+				`d.String() converts d to a string, use zap.Duration`,
+				`t.String() converts t to a string, use zap.Time`,
+				`err.Error() converts err to a string, use zap.NamedError`,
+			},
+		},
+		{
+			name: "time layouts",
+			body: `logger.Info("done", zap.String("day", t.Format(time.DateOnly)))`,
+		},
+		{
+			name: "fmt",
+			body: `zl.Info().Str("count", fmt.Sprint(n)).Str("ok", fmt.Sprintf("%t", ok)).Str("n", fmt.Sprintf("n=%d", n)).Msg("done")`,
+			want: []string{
+				`fmt.Sprint(n) converts n to a string, use Int`,
+				`fmt.Sprintf("%t", ok) converts ok to a string, use Bool`,
+			},
+		},
+		{
+			name: "Any",
+			body: `logger.Info("done", zap.Any("ok", true), zap.Reflect("latency", d), zap.Any("error", err))`,
+			want: []string{
+				`zap.Any hides the bool type of true, use zap.Bool`,
+				`zap.Reflect hides the time.Duration type of d, use zap.Duration`,
+				`zap.Any hides the error type of err, use zap.Error`,
+			},
+		},
+		{
+			name: "slog errors",
+			body: `slog.Info("failed", slog.Any("err", err), slog.String("err", err.Error()))`,
+			want: []string{`err.Error() converts err to a string, use slog.Any`},
+		},
+		{
+			name: "types without a constructor",
+			body: `slog.Info("done", slog.Any("level", lvl), slog.String("level", fmt.Sprint(lvl)), slog.Any("n", []int{n}))`,
+		},
+		{
+			name: "groups and With calls",
+			body: `l := slog.With(slog.Any("ok", ok)); l.Info("done", slog.Group("req", slog.String("count", strconv.Itoa(n))))`,
+			want: []string{
+				`strconv.Itoa(n) converts n to a string, use slog.Int`,
+				`slog.Any hides the bool type of ok, use slog.Bool`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := strings.Replace(typedAttrsSrc, "BODY", tt.body, 1)
+			_, _, diagnostics := checkAttrSource(t, &TypedAttrsRule{}, src)
+
+			if len(diagnostics) != len(tt.want) {
+				t.Fatalf("Check() reported %d diagnostics, want %d: %v", len(diagnostics), len(tt.want), diagnostics)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(diagnostics[i].Message, want) {
+					t.Errorf("Check() message = %q, want %q", diagnostics[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTypedAttrsRuleFix(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "stringified values",
+			body: `slog.Info("done", slog.String("count", strconv.Itoa(n)), slog.String("latency", d.String()))`,
+			want: []string{
+				`slog.Info("done", slog.Int("count", n), slog.String("latency", d.String()))`,
+				`slog.Info("done", slog.String("count", strconv.Itoa(n)), slog.Duration("latency", d))`,
+			},
+		},
+		{
+			name: "function literals",
+			body: `slog.Info("done", slog.String("count", strconv.Itoa(func() int { return n }())))`,
+			want: []string{`slog.Info("done", slog.Int("count", func() int { return n }()))`},
+		},
+		{
+			name: "Any",
+			body: `logger.Info("done", zap.Any("ok", ok))`,
+			want: []string{`logger.Info("done", zap.Bool("ok", ok))`},
+		},
+		{
+			name: "errors under the default key",
+			body: `logger.Info("failed", zap.String("error", err.Error())); zl.Error().AnErr("cause", err).Interface("error", err).Msg("failed")`,
+			want: []string{
+				`logger.Info("failed", zap.Error(err)); zl.Error().AnErr("cause", err).Interface("error", err).Msg("failed")`,
+				`logger.Info("failed", zap.String("error", err.Error())); zl.Error().AnErr("cause", err).Err(err).Msg("failed")`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := strings.Replace(typedAttrsSrc, "BODY", tt.body, 1)
+			pass, _, diagnostics := checkAttrSource(t, &TypedAttrsRule{}, src)
+			start := strings.Index(src, tt.body)
+
+			var fixed []string
+			for _, diag := range diagnostics {
+				for _, fix := range diag.SuggestedFixes {
+					text := applyFix(pass, src, fix)
+					fixed = append(fixed, text[start:start+len(tt.body)+len(text)-len(src)])
+				}
+			}
+
+			if !slices.Equal(fixed, tt.want) {
+				t.Errorf("fixed calls = %q, want %q", fixed, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Package semconv lists the attribute keys of the OpenTelemetry semantic
+// conventions by release, and the ad hoc keys logs commonly use instead of
+// them. It covers the attributes logs usually carry, not the whole registry.
+package semconv
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Versions are the supported releases of the semantic conventions.
+var Versions = []string{
+	"1.20.0", "1.21.0", "1.22.0", "1.23.0", "1.24.0", "1.25.0", "1.26.0",
+	"1.27.0", "1.28.0", "1.29.0", "1.30.0", "1.31.0", "1.32.0",
+}
+
+// Latest is the newest supported release.
+var Latest = Versions[len(Versions)-1]
+
+// key is the name of an attribute from a release on.
+type key struct {
+	since string
+	name  string
+}
+
+// attribute is a piece of data logs carry. Its keys are ordered by release:
+// an attribute renamed by a release has a key for each name, and one
+// introduced after 1.20.0 has none before.
+type attribute struct {
+	keys []key
+	// ad hoc keys used for the attribute, in snake_case
+	synonyms []string
+}
+
+var attributes = []attribute{
+	// HTTP
+	{keys: []key{{"1.20.0", "http.method"}, {"1.21.0", "http.request.method"}}, synonyms: []string{"method", "http_method", "request_method", "verb"}},
+	{keys: []key{{"1.20.0", "http.status_code"}, {"1.21.0", "http.response.status_code"}}, synonyms: []string{"status_code", "http_status", "http_status_code", "response_code"}},
+	{keys: []key{{"1.20.0", "http.route"}}, synonyms: []string{"route", "http_route"}},
+	{keys: []key{{"1.20.0", "http.url"}, {"1.21.0", "url.full"}}, synonyms: []string{"url", "request_url", "uri", "request_uri"}},
+	{keys: []key{{"1.20.0", "http.target"}, {"1.21.0", "url.path"}}, synonyms: []string{"request_path", "url_path"}},
+	{keys: []key{{"1.20.0", "http.scheme"}, {"1.21.0", "url.scheme"}}, synonyms: []string{"scheme"}},
+	{keys: []key{{"1.21.0", "url.query"}}, synonyms: []string{"query_string", "url_query"}},
+	{keys: []key{{"1.20.0", "http.user_agent"}, {"1.21.0", "user_agent.original"}}, synonyms: []string{"user_agent", "useragent", "ua"}},
+
+	// network
+	{keys: []key{{"1.20.0", "net.host.name"}, {"1.21.0", "server.address"}}, synonyms: []string{"server_address", "server_host"}},
+	{keys: []key{{"1.20.0", "net.host.port"}, {"1.21.0", "server.port"}}, synonyms: []string{"server_port"}},
+	{keys: []key{{"1.20.0", "net.sock.peer.addr"}, {"1.21.0", "client.address"}}, synonyms: []string{"client_ip", "client_address", "remote_ip", "remote_addr", "peer_addr"}},
+	{keys: []key{{"1.20.0", "net.sock.peer.port"}, {"1.21.0", "client.port"}}, synonyms: []string{"client_port", "remote_port"}},
+	{keys: []key{{"1.20.0", "net.protocol.name"}, {"1.21.0", "network.protocol.name"}}, synonyms: []string{"protocol", "proto"}},
+
+	// errors
+	{keys: []key{{"1.21.0", "error.type"}}, synonyms: []string{"error_type", "err_type", "error_kind"}},
+	{keys: []key{{"1.32.0", "error.message"}}, synonyms: []string{"err", "error", "error_message", "err_msg", "errmsg"}},
+	{keys: []key{{"1.20.0", "exception.message"}}, synonyms: []string{"exception", "exception_message"}},
+	{keys: []key{{"1.20.0", "exception.type"}}, synonyms: []string{"exception_type"}},
+	{keys: []key{{"1.20.0", "exception.stacktrace"}}, synonyms: []string{"stack", "stacktrace", "stack_trace"}},
+
+	// users
+	{keys: []key{{"1.20.0", "enduser.id"}, {"1.27.0", "user.id"}}, synonyms: []string{"user_id", "userid", "uid"}},
+	{keys: []key{{"1.27.0", "user.email"}}, synonyms: []string{"email", "user_email"}},
+	{keys: []key{{"1.27.0", "user.name"}}, synonyms: []string{"username", "user_name"}},
+
+	// databases
+	{keys: []key{{"1.20.0", "db.system"}, {"1.30.0", "db.system.name"}}, synonyms: []string{"db", "db_system", "db_type", "database"}},
+	{keys: []key{{"1.20.0", "db.name"}, {"1.26.0", "db.namespace"}}, synonyms: []string{"db_name", "dbname", "database_name"}},
+	{keys: []key{{"1.20.0", "db.statement"}, {"1.25.0", "db.query.text"}}, synonyms: []string{"sql", "statement", "db_statement", "db_query"}},
+	{keys: []key{{"1.20.0", "db.operation"}, {"1.26.0", "db.operation.name"}}, synonyms: []string{"db_operation"}},
+	{keys: []key{{"1.20.0", "db.sql.table"}, {"1.26.0", "db.collection.name"}}, synonyms: []string{"table", "table_name", "collection"}},
+
+	// RPC and messaging
+	{keys: []key{{"1.20.0", "rpc.system"}}, synonyms: []string{"rpc_system"}},
+	{keys: []key{{"1.20.0", "rpc.service"}}, synonyms: []string{"rpc_service", "grpc_service"}},
+	{keys: []key{{"1.20.0", "rpc.method"}}, synonyms: []string{"rpc_method", "grpc_method"}},
+	{keys: []key{{"1.20.0", "messaging.system"}}, synonyms: []string{"messaging_system"}},
+	{keys: []key{{"1.20.0", "messaging.destination.name"}}, synonyms: []string{"topic", "queue", "destination"}},
+
+	// code, service and environment
+	{keys: []key{{"1.20.0", "code.function"}, {"1.30.0", "code.function.name"}}, synonyms: []string{"func", "function", "func_name"}},
+	{keys: []key{{"1.20.0", "service.name"}}, synonyms: []string{"service", "service_name", "app", "app_name"}},
+	{keys: []key{{"1.20.0", "service.version"}}, synonyms: []string{"service_version", "app_version"}},
+	{keys: []key{{"1.20.0", "deployment.environment"}, {"1.27.0", "deployment.environment.name"}}, synonyms: []string{"env", "environment"}},
+	{keys: []key{{"1.20.0", "thread.id"}}, synonyms: []string{"thread_id", "tid"}},
+	{keys: []key{{"1.20.0", "process.pid"}}, synonyms: []string{"pid"}},
+	{keys: []key{{"1.20.0", "host.name"}}, synonyms: []string{"hostname", "host_name"}},
+	{keys: []key{{"1.20.0", "k8s.pod.name"}}, synonyms: []string{"pod", "pod_name"}},
+	{keys: []key{{"1.20.0", "cloud.region"}}, synonyms: []string{"region"}},
+}
+
+// Conventions are the attribute keys of a release.
+type Conventions struct {
+	Version string
+	// canonical keys of the release
+	Keys []string
+	// canonical keys by the keys used instead of them: ad hoc keys in
+	// snake_case, and the names of renamed attributes in other releases
+	Renames map[string]string
+}
+
+// Lookup returns the conventions of a release, the latest if version is
+// empty.
+func Lookup(version string) (*Conventions, error) {
+	if version == "" {
+		version = Latest
+	}
+	if !slices.Contains(Versions, version) {
+		return nil, fmt.Errorf("unknown semantic conventions version %q, want one of %s", version, strings.Join(Versions, ", "))
+	}
+
+	conventions := &Conventions{Version: version, Renames: make(map[string]string)}
+
+	for _, attr := range attributes {
+		canonical := ""
+		for _, k := range attr.keys {
+			if compareVersions(k.since, version) <= 0 {
+				canonical = k.name
+			}
+		}
+		if canonical == "" {
+			continue
+		}
+
+		conventions.Keys = append(conventions.Keys, canonical)
+		for _, k := range attr.keys {
+			if k.name != canonical {
+				conventions.Renames[k.name] = canonical
+			}
+		}
+		for _, synonym := range attr.synonyms {
+			conventions.Renames[synonym] = canonical
+		}
+	}
+
+	return conventions, nil
+}
+
+// compareVersions compares releases like 1.26.0.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range min(len(as), len(bs)) {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		if x != y {
+			return x - y
+		}
+	}
+
+	return len(as) - len(bs)
+}
@@ -0,0 +1,89 @@
+package taint
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/ctrlflow"
+	"golang.org/x/tools/go/ssa"
+)
+
+// SSAAnalyzer builds the SSA form of a package like buildssa does, but with
+// debug information and the packages of indirect imports. Taint sources are often recognised by the name of the
+// variable a value was assigned to, and only debug references keep it.
+var SSAAnalyzer = &analysis.Analyzer{
+	Name:       "loglinterssa",
+	Doc:        "builds SSA form with debug information for loglinter",
+	Run:        buildSSA,
+	Requires:   []*analysis.Analyzer{ctrlflow.Analyzer},
+	ResultType: reflect.TypeFor[*SSA](),
+}
+
+// SSA holds the source functions of a package, including function literals.
+type SSA struct {
+	Pkg      *ssa.Package
+	SrcFuncs []*ssa.Function
+}
+
+func buildSSA(pass *analysis.Pass) (any, error) {
+	prog := ssa.NewProgram(pass.Fset, ssa.GlobalDebug)
+
+	if cfgs, ok := pass.ResultOf[ctrlflow.Analyzer].(*ctrlflow.CFGs); ok {
+		prog.SetNoReturn(cfgs.NoReturn)
+	}
+
+	return BuildPackage(prog, pass.Pkg, pass.Files, pass.TypesInfo), nil
+}
+
+// BuildPackage creates and builds the SSA package of type-checked files.
+// Unlike buildssa, it creates the packages of indirect imports too, so that
+// calls into them have a callee package.
+func BuildPackage(prog *ssa.Program, pkg *types.Package, files []*ast.File, info *types.Info) *SSA {
+	created := make(map[*types.Package]bool)
+	var createAll func(pkgs []*types.Package)
+	createAll = func(pkgs []*types.Package) {
+		for _, p := range pkgs {
+			if created[p] {
+				continue
+			}
+			created[p] = true
+			prog.CreatePackage(p, nil, nil, true)
+			createAll(p.Imports())
+		}
+	}
+	createAll(pkg.Imports())
+
+	ssaPkg := prog.CreatePackage(pkg, files, info, false)
+	ssaPkg.Build()
+
+	var funcs []*ssa.Function
+	var addAnons func(fn *ssa.Function)
+	addAnons = func(fn *ssa.Function) {
+		funcs = append(funcs, fn)
+		for _, anon := range fn.AnonFuncs {
+			addAnons(anon)
+		}
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			decl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			obj, ok := info.Defs[decl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+
+			if fn := prog.FuncValue(obj); fn != nil {
+				addAnons(fn)
+			}
+		}
+	}
+
+	return &SSA{Pkg: ssaPkg, SrcFuncs: funcs}
+}
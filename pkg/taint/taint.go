@@ -0,0 +1,734 @@
+// Package taint tracks values from sources like sensitive variables and
+// configured functions through a function's SSA form into call arguments.
+package taint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// Config selects which values are tainted.
+type Config struct {
+	// SensitiveName reports whether a variable or struct field holds tainted
+	// data judging by its name.
+	SensitiveName func(name string) bool
+	// SensitiveType reports whether every value of a type is tainted.
+	SensitiveType func(typ types.Type) bool
+	// SensitiveObject reports whether a variable, struct field or function
+	// result is tainted regardless of its name.
+	SensitiveObject func(obj types.Object) bool
+	// functions whose results are tainted, and struct fields whose values
+	// are, written like (net/http.Request).Header
+	Sources []Source
+	// full names of functions whose results are clean even when their
+	// arguments are tainted, like strconv.Quote
+	Sanitizers []string
+}
+
+// Source is a function whose results are tainted, optionally only when its
+// first argument is a constant matching a pattern.
+type Source struct {
+	// full name, like os.Getenv or (net/http.Header).Get
+	Func string
+	// case-insensitive glob the first argument must match, empty for any
+	Arg string
+}
+
+// ParseSource parses a source written as a function name with an optional
+// argument pattern, like os.Getenv("*SECRET*").
+func ParseSource(s string) (Source, error) {
+	s = strings.TrimSpace(s)
+
+	open := strings.LastIndex(s, "(\"")
+	if open < 0 || !strings.HasSuffix(s, "\")") {
+		if s == "" || strings.ContainsAny(s, "\" ") {
+			return Source{}, fmt.Errorf("invalid taint source %q", s)
+		}
+		return Source{Func: s}, nil
+	}
+
+	source := Source{Func: s[:open], Arg: s[open+2 : len(s)-2]}
+	if _, err := path.Match(strings.ToLower(source.Arg), ""); err != nil {
+		return Source{}, fmt.Errorf("invalid taint source %q: %w", s, err)
+	}
+
+	return source, nil
+}
+
+func (s Source) String() string {
+	if s.Arg == "" {
+		return s.Func
+	}
+	return fmt.Sprintf("%s(%q)", s.Func, s.Arg)
+}
+
+// Step is a point on the way from a source to a sink.
+type Step struct {
+	Pos  token.Pos
+	What string
+}
+
+// Flow is a tainted value reaching an argument of a call.
+type Flow struct {
+	// index of the argument in the call as written
+	Arg int
+	// steps from the source to the call
+	Path []Step
+}
+
+// Source describes where the tainted value came from.
+func (f Flow) Source() string {
+	if len(f.Path) == 0 {
+		return ""
+	}
+	return f.Path[0].What
+}
+
+func (f Flow) String() string {
+	parts := make([]string, len(f.Path))
+	for i, step := range f.Path {
+		parts[i] = step.What
+	}
+	return strings.Join(parts, " -> ")
+}
+
+// Result holds the tainted arguments of every call in a package.
+type Result struct {
+	flows map[token.Pos][]Flow
+}
+
+// Flows returns the tainted arguments of a call.
+func (r *Result) Flows(call *ast.CallExpr) []Flow {
+	if r == nil {
+		return nil
+	}
+	return r.flows[call.Lparen]
+}
+
+// origin links a tainted value to the step it was derived by.
+type origin struct {
+	step Step
+	prev *origin
+}
+
+func (o *origin) then(pos token.Pos, what string) *origin {
+	return &origin{step: Step{Pos: pos, What: what}, prev: o}
+}
+
+func (o *origin) path() []Step {
+	var steps []Step
+	for ; o != nil; o = o.prev {
+		steps = append(steps, o.step)
+	}
+
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+
+	return steps
+}
+
+// Analyze runs the analysis over every function of a package. Values are
+// followed within a function; calls other than known string helpers don't
+// pass taint through.
+func Analyze(pkg *SSA, info *types.Info, cfg Config) *Result {
+	result := &Result{flows: make(map[token.Pos][]Flow)}
+
+	for _, fn := range pkg.SrcFuncs {
+		a := &tracker{cfg: cfg, info: info, tainted: make(map[ssa.Value]*origin), fields: make(map[ssa.Value]map[int]*origin)}
+		a.seed(fn)
+		a.propagate()
+		a.collect(fn, result)
+	}
+
+	return result
+}
+
+type tracker struct {
+	cfg     Config
+	info    *types.Info
+	tainted map[ssa.Value]*origin
+	// structs, and addresses of structs, holding tainted values in some of
+	// their fields only, by field index
+	fields map[ssa.Value]map[int]*origin
+	queue  []ssa.Value
+}
+
+func (a *tracker) mark(v ssa.Value, o *origin) {
+	if v == nil {
+		return
+	}
+	if _, ok := a.tainted[v]; ok {
+		return
+	}
+
+	a.tainted[v] = o
+	a.queue = append(a.queue, v)
+}
+
+// markField taints a field of a struct, or of the struct an address points
+// to, leaving its other fields clean. The struct is itself a field of
+// another when v is a field address.
+func (a *tracker) markField(v ssa.Value, field int, o *origin) {
+	if _, ok := a.tainted[v]; ok {
+		return
+	}
+	if _, ok := a.fields[v][field]; ok {
+		return
+	}
+
+	if a.fields[v] == nil {
+		a.fields[v] = make(map[int]*origin)
+	}
+	a.fields[v][field] = o
+	a.queue = append(a.queue, v)
+
+	switch v := v.(type) {
+	case *ssa.FieldAddr:
+		a.markField(v.X, v.Field, o)
+	case *ssa.IndexAddr:
+		a.mark(v.X, o)
+	}
+}
+
+// markStored taints what a store to addr writes into: a single field of a
+// struct, or a whole variable or array.
+func (a *tracker) markStored(addr ssa.Value, o *origin) {
+	a.mark(addr, o)
+
+	for {
+		switch v := addr.(type) {
+		case *ssa.FieldAddr:
+			a.markField(v.X, v.Field, o)
+			return
+		case *ssa.IndexAddr:
+			addr = v.X
+			a.mark(addr, o)
+		default:
+			return
+		}
+	}
+}
+
+// fieldOrigin returns the origin of the tainted field of a struct with the
+// lowest index.
+func (a *tracker) fieldOrigin(v ssa.Value) *origin {
+	fields := a.fields[v]
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return fields[slices.Min(slices.Collect(maps.Keys(fields)))]
+}
+
+func (a *tracker) seed(fn *ssa.Function) {
+	for _, param := range fn.Params {
+		if a.sensitiveName(param.Name()) || a.sensitiveType(param.Type()) {
+			a.mark(param, &origin{step: Step{Pos: param.Pos(), What: param.Name()}})
+		}
+	}
+
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			switch instr := instr.(type) {
+			case *ssa.DebugRef:
+				if obj := a.refObject(instr); obj != nil && (a.sensitiveName(obj.Name()) || a.sensitiveObject(obj) || a.sourceField(instr)) {
+					a.mark(instr.X, &origin{step: Step{Pos: instr.Expr.Pos(), What: types.ExprString(instr.Expr)}})
+				}
+			case *ssa.Call:
+				if source, ok := a.matchSource(instr.Common()); ok {
+					a.mark(instr, &origin{step: Step{Pos: instr.Pos(), What: source}})
+				} else if fn := calleeFunc(instr.Common()); fn != nil && a.sensitiveObject(fn) {
+					a.mark(instr, &origin{step: Step{Pos: instr.Pos(), What: fn.FullName()}})
+				}
+			}
+
+			if v, ok := instr.(ssa.Value); ok && a.sensitiveType(v.Type()) {
+				a.mark(v, &origin{step: Step{Pos: v.Pos(), What: "value of " + types.TypeString(v.Type(), nil)}})
+			}
+		}
+	}
+}
+
+// refObject returns the variable or field a debug reference reads.
+func (a *tracker) refObject(ref *ssa.DebugRef) types.Object {
+	if obj, ok := ref.Object().(*types.Var); ok {
+		return obj
+	}
+
+	sel, ok := ref.Expr.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	selection, ok := a.info.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return nil
+	}
+
+	return selection.Obj()
+}
+
+func (a *tracker) sensitiveName(name string) bool {
+	return a.cfg.SensitiveName != nil && name != "_" && a.cfg.SensitiveName(name)
+}
+
+func (a *tracker) sensitiveType(typ types.Type) bool {
+	return a.cfg.SensitiveType != nil && a.cfg.SensitiveType(typ)
+}
+
+func (a *tracker) sensitiveObject(obj types.Object) bool {
+	return a.cfg.SensitiveObject != nil && a.cfg.SensitiveObject(obj)
+}
+
+// sourceField reports whether a debug reference reads a struct field
+// configured as a source.
+func (a *tracker) sourceField(ref *ssa.DebugRef) bool {
+	sel, ok := ref.Expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	selection, ok := a.info.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return false
+	}
+
+	recv := selection.Recv()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := types.Unalias(recv).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+
+	name := fmt.Sprintf("(%s.%s).%s", named.Obj().Pkg().Path(), named.Obj().Name(), sel.Sel.Name)
+	for _, source := range a.cfg.Sources {
+		if source.Func == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *tracker) matchSource(call *ssa.CallCommon) (string, bool) {
+	callee := calleeName(call)
+	if callee == "" {
+		return "", false
+	}
+
+	for _, source := range a.cfg.Sources {
+		if source.Func != callee {
+			continue
+		}
+
+		if source.Arg == "" {
+			return callee, true
+		}
+
+		args := call.Args
+		if call.Signature().Recv() != nil && !call.IsInvoke() {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		c, ok := args[0].(*ssa.Const)
+		if !ok || c.Value == nil || c.Value.Kind() != constant.String {
+			continue
+		}
+
+		arg := constant.StringVal(c.Value)
+		if matched, _ := path.Match(strings.ToLower(source.Arg), strings.ToLower(arg)); matched {
+			return fmt.Sprintf("%s(%q)", callee, arg), true
+		}
+	}
+
+	return "", false
+}
+
+// calleeFunc returns the called function or interface method, nil for
+// closures and dynamic calls.
+func calleeFunc(call *ssa.CallCommon) *types.Func {
+	if call.IsInvoke() {
+		return call.Method
+	}
+
+	if fn := call.StaticCallee(); fn != nil {
+		obj, _ := fn.Object().(*types.Func)
+		return obj
+	}
+
+	return nil
+}
+
+// calleeName returns the full name of a called function or interface method.
+func calleeName(call *ssa.CallCommon) string {
+	if call.IsInvoke() {
+		return call.Method.FullName()
+	}
+
+	if fn := call.StaticCallee(); fn != nil {
+		if obj, ok := fn.Object().(*types.Func); ok {
+			return obj.FullName()
+		}
+		return fn.String()
+	}
+
+	return ""
+}
+
+func (a *tracker) propagate() {
+	for len(a.queue) > 0 {
+		v := a.queue[0]
+		a.queue = a.queue[1:]
+
+		refs := v.Referrers()
+		if refs == nil {
+			continue
+		}
+
+		o, ok := a.tainted[v]
+		for _, instr := range *refs {
+			if ok {
+				a.step(instr, v, o)
+			} else {
+				a.stepFields(instr, v)
+			}
+		}
+	}
+}
+
+// stepFields follows a struct tainted in some of its fields: reading one
+// of them, copying the struct, or using it whole, which taints the use.
+func (a *tracker) stepFields(instr ssa.Instruction, v ssa.Value) {
+	switch instr := instr.(type) {
+	case *ssa.FieldAddr:
+		if o, ok := a.fields[v][instr.Field]; ok {
+			a.mark(instr, o)
+		}
+	case *ssa.Field:
+		if o, ok := a.fields[v][instr.Field]; ok {
+			a.mark(instr, o)
+		}
+
+	case *ssa.UnOp:
+		if instr.Op == token.MUL {
+			for field, o := range a.fields[v] {
+				a.markField(instr, field, o)
+			}
+		}
+	case *ssa.Phi, *ssa.ChangeType:
+		for field, o := range a.fields[v] {
+			a.markField(instr.(ssa.Value), field, o)
+		}
+	case *ssa.Store:
+		if instr.Val == v {
+			for field, o := range a.fields[v] {
+				a.markField(instr.Addr, field, o)
+			}
+		}
+
+	case *ssa.MakeInterface:
+		a.mark(instr, a.fieldOrigin(v))
+	}
+}
+
+func (a *tracker) step(instr ssa.Instruction, v ssa.Value, o *origin) {
+	switch instr := instr.(type) {
+	case *ssa.BinOp:
+		if instr.Op == token.ADD && isString(instr.Type()) {
+			a.mark(instr, o.then(instr.Pos(), "string concatenation"))
+		}
+
+	case *ssa.UnOp:
+		if instr.Op == token.MUL {
+			a.mark(instr, o)
+		}
+
+	case *ssa.Convert, *ssa.ChangeType, *ssa.MakeInterface, *ssa.ChangeInterface,
+		*ssa.TypeAssert, *ssa.Phi, *ssa.Slice, *ssa.SliceToArrayPointer:
+		a.mark(instr.(ssa.Value), o)
+
+	case *ssa.Extract:
+		// the error of a source, or the ok of a lookup, carry no data
+		if !isErrorOrBool(instr.Type()) {
+			a.mark(instr, o)
+		}
+
+	case *ssa.Field:
+		a.mark(instr, o)
+	case *ssa.FieldAddr:
+		a.mark(instr, o)
+	case *ssa.Index:
+		if instr.X == v {
+			a.mark(instr, o)
+		}
+	case *ssa.IndexAddr:
+		if instr.X == v {
+			a.mark(instr, o)
+		}
+	case *ssa.Lookup:
+		if instr.X == v {
+			a.mark(instr, o)
+		}
+
+	case *ssa.Store:
+		if instr.Val == v {
+			a.markStored(instr.Addr, o)
+		}
+
+	case *ssa.Call:
+		a.stepCall(instr, o)
+	}
+}
+
+func (a *tracker) stepCall(call *ssa.Call, o *origin) {
+	common := call.Common()
+
+	if builtin, ok := common.Value.(*ssa.Builtin); ok {
+		if builtin.Name() == "append" {
+			a.mark(call, o)
+		}
+		return
+	}
+
+	fn := common.StaticCallee()
+	if fn == nil {
+		return
+	}
+
+	obj, ok := fn.Object().(*types.Func)
+	if !ok || obj.Pkg() == nil {
+		return
+	}
+
+	if slices.Contains(a.cfg.Sanitizers, obj.FullName()) {
+		return
+	}
+
+	recv := obj.Type().(*types.Signature).Recv()
+
+	switch {
+	case isDecoder(obj):
+		// decoding tainted input taints the values decoded into
+		if obj.Name() == "NewDecoder" {
+			a.mark(call, o.then(call.Pos(), obj.FullName()))
+			break
+		}
+		for _, arg := range common.Args {
+			if root := rootAlloc(arg); root != nil {
+				a.mark(root, o.then(call.Pos(), obj.FullName()))
+			}
+		}
+
+	case recv != nil && isBuffer(recv.Type()):
+		// writes taint the buffer, String and Bytes read it back
+		if strings.HasPrefix(obj.Name(), "Write") && len(common.Args) > 0 {
+			a.mark(common.Args[0], o)
+			if root := rootAlloc(common.Args[0]); root != nil {
+				a.mark(root, o)
+			}
+		} else if obj.Name() == "String" || obj.Name() == "Bytes" {
+			a.mark(call, o)
+		}
+
+	case isPropagator(obj):
+		a.mark(call, o.then(call.Pos(), obj.FullName()))
+
+	case obj.Pkg().Path() == "fmt" && strings.HasPrefix(obj.Name(), "Fprint") && len(common.Args) > 0:
+		if root := rootAlloc(common.Args[0]); root != nil {
+			a.mark(root, o.then(call.Pos(), obj.FullName()))
+		}
+	}
+}
+
+// isPropagator reports whether a function returns data derived from its
+// arguments: fmt's Sprint family, io.ReadAll and the string helpers of the
+// standard library. Helpers like strings.Contains or strconv.Atoi, which
+// return no text, don't.
+func isPropagator(fn *types.Func) bool {
+	switch fn.Pkg().Path() {
+	case "io":
+		return fn.Name() == "ReadAll"
+	case "fmt":
+		return strings.HasPrefix(fn.Name(), "Sprint") || fn.Name() == "Errorf" || fn.Name() == "Append"
+	case "strings", "bytes", "strconv", "encoding/hex", "encoding/base64", "net/url", "path", "path/filepath":
+		results := fn.Type().(*types.Signature).Results()
+		for i := range results.Len() {
+			if isText(results.At(i).Type()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isText reports whether a type is a string, []byte or []string.
+func isText(typ types.Type) bool {
+	if slice, ok := typ.Underlying().(*types.Slice); ok {
+		elem, ok := slice.Elem().Underlying().(*types.Basic)
+		return ok && (elem.Kind() == types.Byte || elem.Kind() == types.String)
+	}
+
+	return isString(typ)
+}
+
+func isErrorOrBool(typ types.Type) bool {
+	if types.Identical(typ, types.Universe.Lookup("error").Type()) {
+		return true
+	}
+
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsBoolean != 0
+}
+
+// isDecoder reports whether a function decodes data into values passed to it:
+// Unmarshal, NewDecoder and Decode of the encoding packages.
+func isDecoder(fn *types.Func) bool {
+	switch fn.Pkg().Path() {
+	case "encoding/json", "encoding/xml", "encoding/gob":
+		return fn.Name() == "Unmarshal" || fn.Name() == "NewDecoder" || fn.Name() == "Decode"
+	}
+
+	return false
+}
+
+func isBuffer(typ types.Type) bool {
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+
+	switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+	case "strings.Builder", "bytes.Buffer":
+		return true
+	}
+
+	return false
+}
+
+// rootAlloc returns the local variable or array an address, possibly passed
+// as an interface, points into.
+func rootAlloc(addr ssa.Value) *ssa.Alloc {
+	for {
+		switch v := addr.(type) {
+		case *ssa.Alloc:
+			return v
+		case *ssa.IndexAddr:
+			addr = v.X
+		case *ssa.FieldAddr:
+			addr = v.X
+		case *ssa.MakeInterface:
+			addr = v.X
+		default:
+			return nil
+		}
+	}
+}
+
+func isString(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// collect records tainted arguments of every call in fn, including the
+// elements of variadic argument slices.
+func (a *tracker) collect(fn *ssa.Function, result *Result) {
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+
+			common := call.Common()
+			sig := common.Signature()
+			args := common.Args
+			// static method calls pass the receiver first
+			offset := len(args) - sig.Params().Len()
+			if offset < 0 {
+				continue
+			}
+
+			for i, arg := range args[offset:] {
+				if sig.Variadic() && i == sig.Params().Len()-1 {
+					if elems := a.variadicTaint(arg); len(elems) > 0 {
+						for elem, o := range elems {
+							result.add(common.Pos(), Flow{Arg: i + elem, Path: o.path()})
+						}
+						continue
+					}
+				}
+
+				if o, ok := a.tainted[arg]; ok {
+					result.add(common.Pos(), Flow{Arg: i, Path: o.path()})
+				} else if o := a.fieldOrigin(arg); o != nil {
+					result.add(common.Pos(), Flow{Arg: i, Path: o.path()})
+				}
+			}
+		}
+	}
+}
+
+// variadicTaint returns the tainted elements of a variadic argument slice
+// built in place, keyed by their index.
+func (a *tracker) variadicTaint(arg ssa.Value) map[int]*origin {
+	slice, ok := arg.(*ssa.Slice)
+	if !ok {
+		return nil
+	}
+
+	alloc, ok := slice.X.(*ssa.Alloc)
+	if !ok || alloc.Referrers() == nil {
+		return nil
+	}
+
+	elems := make(map[int]*origin)
+	for _, ref := range *alloc.Referrers() {
+		indexAddr, ok := ref.(*ssa.IndexAddr)
+		if !ok || indexAddr.Referrers() == nil {
+			continue
+		}
+
+		index, ok := indexAddr.Index.(*ssa.Const)
+		if !ok {
+			continue
+		}
+
+		for _, use := range *indexAddr.Referrers() {
+			store, ok := use.(*ssa.Store)
+			if !ok {
+				continue
+			}
+			if o, ok := a.tainted[store.Val]; ok {
+				elems[int(index.Int64())] = o
+			}
+		}
+	}
+
+	return elems
+}
+
+func (r *Result) add(pos token.Pos, flow Flow) {
+	if pos.IsValid() {
+		r.flows[pos] = append(r.flows[pos], flow)
+	}
+}
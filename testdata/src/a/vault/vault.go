@@ -0,0 +1,17 @@
+package vault
+
+// Token authenticates calls to the vault.
+//
+//loglinter:sensitive
+type Token string // want "sensitive type Token has no redacting LogValue method"
+
+// ReadSecret returns the secret stored at path.
+//
+//loglinter:secret-source
+func ReadSecret(path string) string { return path }
+
+// Config tells how to reach the vault.
+type Config struct { // want "sensitive type Config has no redacting LogValue method"
+	Host string
+	Pin  string `loglinter:"sensitive"`
+}
@@ -0,0 +1,19 @@
+// Package log is a minimal stand-in for github.com/rs/zerolog/log used by
+// tests.
+package log
+
+import "github.com/rs/zerolog"
+
+var Logger = zerolog.New(nil)
+
+func Trace() *zerolog.Event                        { return Logger.Trace() }
+func Debug() *zerolog.Event                        { return Logger.Debug() }
+func Info() *zerolog.Event                         { return Logger.Info() }
+func Warn() *zerolog.Event                         { return Logger.Warn() }
+func Error() *zerolog.Event                        { return Logger.Error() }
+func Err(err error) *zerolog.Event                 { return Logger.Err(err) }
+func Fatal() *zerolog.Event                        { return Logger.Fatal() }
+func Panic() *zerolog.Event                        { return Logger.Panic() }
+func WithLevel(level zerolog.Level) *zerolog.Event { return Logger.WithLevel(level) }
+func Print(v ...any)                               { Logger.Print(v...) }
+func Printf(format string, v ...any)               { Logger.Printf(format, v...) }
@@ -0,0 +1,72 @@
+// Package zerolog is a minimal stand-in for github.com/rs/zerolog used by
+// tests.
+package zerolog
+
+import (
+	"fmt"
+	"time"
+)
+
+type Level int8
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	FatalLevel
+	PanicLevel
+	NoLevel
+	Disabled
+
+	TraceLevel Level = -1
+)
+
+type Logger struct{}
+
+func New(w any) Logger                                  { return Logger{} }
+func (l Logger) Trace() *Event                          { return &Event{} }
+func (l *Logger) Debug() *Event                         { return &Event{} }
+func (l *Logger) Info() *Event                          { return &Event{} }
+func (l *Logger) Warn() *Event                          { return &Event{} }
+func (l *Logger) Error() *Event                         { return &Event{} }
+func (l *Logger) Err(err error) *Event                  { return &Event{} }
+func (l *Logger) Fatal() *Event                         { return &Event{} }
+func (l *Logger) Panic() *Event                         { return &Event{} }
+func (l *Logger) WithLevel(level Level) *Event          { return &Event{} }
+func (l *Logger) Log() *Event                           { return &Event{} }
+func (l Logger) Print(v ...any)                         {}
+func (l Logger) Printf(format string, v ...any)         {}
+func (l Logger) With() Context                          { return Context{} }
+func (l Logger) Level(lvl Level) Logger                 { return l }
+func (l Logger) Output(w any) Logger                    { return l }
+func (l *Logger) UpdateContext(func(c Context) Context) {}
+
+type Context struct{}
+
+func (c Context) Str(key, val string) Context { return c }
+func (c Context) Logger() Logger              { return Logger{} }
+
+type Event struct{}
+
+func Dict() *Event                                            { return &Event{} }
+func (e *Event) Str(key, val string) *Event                   { return e }
+func (e *Event) Strs(key string, vals []string) *Event        { return e }
+func (e *Event) Int(key string, i int) *Event                 { return e }
+func (e *Event) Int64(key string, i int64) *Event             { return e }
+func (e *Event) Uint64(key string, i uint64) *Event           { return e }
+func (e *Event) Bool(key string, b bool) *Event               { return e }
+func (e *Event) Float64(key string, f float64) *Event         { return e }
+func (e *Event) Dur(key string, d time.Duration) *Event       { return e }
+func (e *Event) Time(key string, t time.Time) *Event          { return e }
+func (e *Event) Any(key string, i any) *Event                 { return e }
+func (e *Event) Interface(key string, i any) *Event           { return e }
+func (e *Event) Stringer(key string, val fmt.Stringer) *Event { return e }
+func (e *Event) Dict(key string, dict *Event) *Event          { return e }
+func (e *Event) Err(err error) *Event                         { return e }
+func (e *Event) AnErr(key string, err error) *Event           { return e }
+func (e *Event) Caller(skip ...int) *Event                    { return e }
+func (e *Event) Timestamp() *Event                            { return e }
+func (e *Event) Msg(msg string)                               {}
+func (e *Event) Msgf(format string, v ...any)                 {}
+func (e *Event) Send()                                        {}
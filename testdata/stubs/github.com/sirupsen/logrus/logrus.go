@@ -0,0 +1,91 @@
+// Package logrus is a minimal stand-in for github.com/sirupsen/logrus used
+// by tests.
+package logrus
+
+import "context"
+
+type Level uint32
+
+const (
+	PanicLevel Level = iota
+	FatalLevel
+	ErrorLevel
+	WarnLevel
+	InfoLevel
+	DebugLevel
+	TraceLevel
+)
+
+type Fields map[string]interface{}
+
+type Logger struct{}
+
+func New() *Logger               { return &Logger{} }
+func StandardLogger() *Logger    { return &Logger{} }
+func (l *Logger) SetLevel(Level) {}
+
+func (l *Logger) WithField(key string, value interface{}) *Entry       { return &Entry{} }
+func (l *Logger) WithFields(fields Fields) *Entry                      { return &Entry{} }
+func (l *Logger) WithError(err error) *Entry                           { return &Entry{} }
+func (l *Logger) WithContext(ctx context.Context) *Entry               { return &Entry{} }
+func (l *Logger) Log(level Level, args ...interface{})                 {}
+func (l *Logger) Logf(level Level, format string, args ...interface{}) {}
+func (l *Logger) Trace(args ...interface{})                            {}
+func (l *Logger) Debug(args ...interface{})                            {}
+func (l *Logger) Info(args ...interface{})                             {}
+func (l *Logger) Print(args ...interface{})                            {}
+func (l *Logger) Warn(args ...interface{})                             {}
+func (l *Logger) Warning(args ...interface{})                          {}
+func (l *Logger) Error(args ...interface{})                            {}
+func (l *Logger) Fatal(args ...interface{})                            {}
+func (l *Logger) Panic(args ...interface{})                            {}
+func (l *Logger) Debugf(format string, args ...interface{})            {}
+func (l *Logger) Infof(format string, args ...interface{})             {}
+func (l *Logger) Warnf(format string, args ...interface{})             {}
+func (l *Logger) Errorf(format string, args ...interface{})            {}
+func (l *Logger) Infoln(args ...interface{})                           {}
+
+type Entry struct {
+	Data Fields
+}
+
+func NewEntry(logger *Logger) *Entry                                  { return &Entry{} }
+func (e *Entry) WithField(key string, value interface{}) *Entry       { return e }
+func (e *Entry) WithFields(fields Fields) *Entry                      { return e }
+func (e *Entry) WithError(err error) *Entry                           { return e }
+func (e *Entry) WithContext(ctx context.Context) *Entry               { return e }
+func (e *Entry) Log(level Level, args ...interface{})                 {}
+func (e *Entry) Logf(level Level, format string, args ...interface{}) {}
+func (e *Entry) Trace(args ...interface{})                            {}
+func (e *Entry) Debug(args ...interface{})                            {}
+func (e *Entry) Info(args ...interface{})                             {}
+func (e *Entry) Print(args ...interface{})                            {}
+func (e *Entry) Warn(args ...interface{})                             {}
+func (e *Entry) Warning(args ...interface{})                          {}
+func (e *Entry) Error(args ...interface{})                            {}
+func (e *Entry) Fatal(args ...interface{})                            {}
+func (e *Entry) Panic(args ...interface{})                            {}
+func (e *Entry) Debugf(format string, args ...interface{})            {}
+func (e *Entry) Infof(format string, args ...interface{})             {}
+func (e *Entry) Warnf(format string, args ...interface{})             {}
+func (e *Entry) Errorf(format string, args ...interface{})            {}
+func (e *Entry) Infoln(args ...interface{})                           {}
+
+func WithField(key string, value interface{}) *Entry { return &Entry{} }
+func WithFields(fields Fields) *Entry                { return &Entry{} }
+func WithError(err error) *Entry                     { return &Entry{} }
+func WithContext(ctx context.Context) *Entry         { return &Entry{} }
+func Trace(args ...interface{})                      {}
+func Debug(args ...interface{})                      {}
+func Info(args ...interface{})                       {}
+func Print(args ...interface{})                      {}
+func Warn(args ...interface{})                       {}
+func Warning(args ...interface{})                    {}
+func Error(args ...interface{})                      {}
+func Fatal(args ...interface{})                      {}
+func Panic(args ...interface{})                      {}
+func Debugf(format string, args ...interface{})      {}
+func Infof(format string, args ...interface{})       {}
+func Warnf(format string, args ...interface{})       {}
+func Errorf(format string, args ...interface{})      {}
+func Infoln(args ...interface{})                     {}
@@ -0,0 +1,47 @@
+// Package zap is a minimal stand-in for go.uber.org/zap used by tests.
+package zap
+
+import (
+	"fmt"
+	"time"
+)
+
+type Field struct {
+	Key    string
+	String string
+}
+
+func String(key, val string) Field           { return Field{Key: key, String: val} }
+func Int(key string, val int) Field          { return Field{Key: key} }
+func Int64(key string, val int64) Field      { return Field{Key: key} }
+func Uint64(key string, val uint64) Field    { return Field{Key: key} }
+func Float64(key string, val float64) Field  { return Field{Key: key} }
+func Bool(key string, val bool) Field        { return Field{Key: key} }
+func Error(err error) Field                  { return Field{Key: "error"} }
+func NamedError(key string, err error) Field { return Field{Key: key} }
+func Dict(key string, val ...Field) Field    { return Field{Key: key} }
+func Any(key string, val any) Field          { return Field{Key: key} }
+func Reflect(key string, val any) Field      { return Field{Key: key} }
+
+func Duration(key string, val time.Duration) Field { return Field{Key: key} }
+func Time(key string, val time.Time) Field         { return Field{Key: key} }
+func Stringer(key string, val fmt.Stringer) Field  { return Field{Key: key} }
+
+type Logger struct{}
+
+func L() *Logger                                     { return &Logger{} }
+func (l *Logger) Info(msg string, fields ...Field)   {}
+func (l *Logger) Error(msg string, fields ...Field)  {}
+func (l *Logger) DPanic(msg string, fields ...Field) {}
+func (l *Logger) Sync() error                        { return nil }
+func (l *Logger) With(fields ...Field) *Logger       { return l }
+func (l *Logger) Sugar() *SugaredLogger              { return &SugaredLogger{} }
+
+type SugaredLogger struct{}
+
+func (s *SugaredLogger) Info(args ...any)                        {}
+func (s *SugaredLogger) Infof(template string, args ...any)      {}
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...any)  {}
+func (s *SugaredLogger) Fatalw(msg string, keysAndValues ...any) {}
+func (s *SugaredLogger) Infoln(args ...any)                      {}
+func (s *SugaredLogger) With(args ...any) *SugaredLogger         { return s }